@@ -0,0 +1,232 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BiMap is an ordered bidirectional map: on top of looking up values by key, it allows looking up
+// keys by value in constant time. Values must then be unique; what happens when setting a value that's
+// already associated with another key is determined by the map's CollisionPolicy.
+// It's marshalled to and unmarshalled from JSON and YAML the same way as an OrderedMap.
+//
+// Its zero value is only usable to unmarshal into; instances must otherwise be created with NewBiMap.
+type BiMap[K, V comparable] struct {
+	om              *OrderedMap[K, V]
+	keys            map[V]K
+	collisionPolicy CollisionPolicy
+}
+
+var (
+	_ json.Marshaler   = &BiMap[int, int]{}
+	_ json.Unmarshaler = &BiMap[int, int]{}
+	_ yaml.Marshaler   = &BiMap[int, int]{}
+	_ yaml.Unmarshaler = &BiMap[int, int]{}
+)
+
+// CollisionPolicy determines what a BiMap does when setting a value already associated with another key.
+type CollisionPolicy int
+
+const (
+	// RejectCollisions makes Set return a ValueCollisionError, and leave the map untouched.
+	// This is the default policy.
+	RejectCollisions CollisionPolicy = iota
+	// ReplaceOnCollision makes Set delete the other key.
+	ReplaceOnCollision
+)
+
+// ValueCollisionError is returned by BiMap.Set when trying to set a value that's already associated
+// with another key, with the RejectCollisions policy.
+type ValueCollisionError[K, V comparable] struct {
+	Key         K
+	Value       V
+	ExistingKey K
+}
+
+func (e *ValueCollisionError[K, V]) Error() string {
+	return fmt.Sprintf("cannot set %v to %v: value already associated with %v", e.Key, e.Value, e.ExistingKey)
+}
+
+// BiMapOption configures a BiMap.
+type BiMapOption[K, V comparable] func(m *BiMap[K, V])
+
+// WithCollisionPolicy sets the BiMap's CollisionPolicy; the default is RejectCollisions.
+func WithCollisionPolicy[K, V comparable](policy CollisionPolicy) BiMapOption[K, V] {
+	return func(m *BiMap[K, V]) {
+		m.collisionPolicy = policy
+	}
+}
+
+// NewBiMap creates a new BiMap.
+func NewBiMap[K, V comparable](options ...BiMapOption[K, V]) *BiMap[K, V] {
+	m := &BiMap[K, V]{}
+	m.initialize()
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+func (m *BiMap[K, V]) initialize() {
+	m.om = New[K, V]()
+	m.keys = make(map[V]K)
+}
+
+// Get looks for the given key, and returns the value associated with it,
+// or V's nil value if not found. The boolean it returns says whether the key is present in the map.
+func (m *BiMap[K, V]) Get(key K) (V, bool) {
+	return m.om.Get(key)
+}
+
+// GetKey looks for the given value, and returns the key associated with it,
+// or K's nil value if not found. The boolean it returns says whether the value is present in the map.
+func (m *BiMap[K, V]) GetKey(value V) (key K, present bool) {
+	key, present = m.keys[value]
+	return
+}
+
+// Set sets the key-value pair, and returns what Get would have returned on that key prior to the call.
+// If value is already associated with another key, then depending on the map's CollisionPolicy,
+// either a ValueCollisionError is returned and the map is left untouched, or the other key is deleted.
+// As with OrderedMap.Set, updating an existing key doesn't change its position.
+func (m *BiMap[K, V]) Set(key K, value V) (val V, present bool, err error) {
+	if existingKey, collision := m.keys[value]; collision && existingKey != key {
+		if m.collisionPolicy == RejectCollisions {
+			return val, false, &ValueCollisionError[K, V]{Key: key, Value: value, ExistingKey: existingKey}
+		}
+		m.om.Delete(existingKey)
+	}
+
+	val, present = m.om.Set(key, value)
+	if present {
+		delete(m.keys, val)
+	}
+	m.keys[value] = key
+
+	return
+}
+
+// Delete removes the key-value pair, and returns what Get would have returned on that key prior to the call.
+func (m *BiMap[K, V]) Delete(key K) (val V, present bool) {
+	if val, present = m.om.Delete(key); present {
+		delete(m.keys, val)
+	}
+	return
+}
+
+// DeleteValue removes the key-value pair associated with value, and returns what GetKey would have
+// returned on that value prior to the call.
+func (m *BiMap[K, V]) DeleteValue(value V) (key K, present bool) {
+	if key, present = m.keys[value]; present {
+		m.om.Delete(key)
+		delete(m.keys, value)
+	}
+	return
+}
+
+// Len returns the length of the map.
+func (m *BiMap[K, V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.om.Len()
+}
+
+// MoveAfter moves the pair associated with key to its new position after the one associated with markKey.
+// Returns an error iff key or markKey are not present in the map. If an error is returned,
+// it will be a KeyNotFoundError.
+func (m *BiMap[K, V]) MoveAfter(key, markKey K) error {
+	return m.om.MoveAfter(key, markKey)
+}
+
+// MoveBefore moves the pair associated with key to its new position before the one associated with markKey.
+// Returns an error iff key or markKey are not present in the map. If an error is returned,
+// it will be a KeyNotFoundError.
+func (m *BiMap[K, V]) MoveBefore(key, markKey K) error {
+	return m.om.MoveBefore(key, markKey)
+}
+
+// MoveToBack moves the pair associated with key to the back of the map.
+// Returns an error iff key is not present in the map. If an error is returned,
+// it will be a KeyNotFoundError.
+func (m *BiMap[K, V]) MoveToBack(key K) error {
+	return m.om.MoveToBack(key)
+}
+
+// MoveToFront moves the pair associated with key to the front of the map.
+// Returns an error iff key is not present in the map. If an error is returned,
+// it will be a KeyNotFoundError.
+func (m *BiMap[K, V]) MoveToFront(key K) error {
+	return m.om.MoveToFront(key)
+}
+
+// FromOldest returns an iterator over all the key-value pairs in the map, starting from the oldest pair.
+func (m *BiMap[K, V]) FromOldest() iter.Seq2[K, V] {
+	return m.om.FromOldest()
+}
+
+// FromNewest returns an iterator over all the key-value pairs in the map, starting from the newest pair.
+func (m *BiMap[K, V]) FromNewest() iter.Seq2[K, V] {
+	return m.om.FromNewest()
+}
+
+// KeysFromOldest returns an iterator over all the keys in the map, starting from the oldest pair.
+func (m *BiMap[K, V]) KeysFromOldest() iter.Seq[K] {
+	return m.om.KeysFromOldest()
+}
+
+// ValuesFromOldest returns an iterator over all the values in the map, starting from the oldest pair.
+func (m *BiMap[K, V]) ValuesFromOldest() iter.Seq[V] {
+	return m.om.ValuesFromOldest()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *BiMap[K, V]) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	return m.om.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Decoded pairs are set one after the other, and so are subject to the map's CollisionPolicy.
+func (m *BiMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoded := New[K, V]()
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return m.setAll(decoded)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (m *BiMap[K, V]) MarshalYAML() (interface{}, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	return m.om.MarshalYAML()
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// Decoded pairs are set one after the other, and so are subject to the map's CollisionPolicy.
+func (m *BiMap[K, V]) UnmarshalYAML(value *yaml.Node) error {
+	decoded := New[K, V]()
+	if err := decoded.UnmarshalYAML(value); err != nil {
+		return err
+	}
+	return m.setAll(decoded)
+}
+
+func (m *BiMap[K, V]) setAll(om *OrderedMap[K, V]) error {
+	if m.om == nil {
+		m.initialize()
+	}
+	for key, value := range om.FromOldest() {
+		if _, _, err := m.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[string, int]()
+
+	_, present, err := m.Set("one", 1)
+	require.NoError(t, err)
+	assert.False(t, present)
+	_, _, err = m.Set("two", 2)
+	require.NoError(t, err)
+	_, _, err = m.Set("three", 3)
+	require.NoError(t, err)
+
+	value, present := m.Get("two")
+	assert.True(t, present)
+	assert.Equal(t, 2, value)
+
+	key, present := m.GetKey(3)
+	assert.True(t, present)
+	assert.Equal(t, "three", key)
+	_, present = m.GetKey(28)
+	assert.False(t, present)
+
+	// updating a key
+	previous, present, err := m.Set("two", 20)
+	require.NoError(t, err)
+	assert.True(t, present)
+	assert.Equal(t, 2, previous)
+	_, present = m.GetKey(2)
+	assert.False(t, present)
+	key, _ = m.GetKey(20)
+	assert.Equal(t, "two", key)
+
+	// setting the same pair again is fine
+	_, _, err = m.Set("two", 20)
+	require.NoError(t, err)
+
+	// collisions are rejected by default
+	_, _, err = m.Set("four", 1)
+	assert.Equal(t, &ValueCollisionError[string, int]{Key: "four", Value: 1, ExistingKey: "one"}, err)
+	assert.EqualError(t, err, "cannot set four to 1: value already associated with one")
+	_, _, err = m.Set("two", 3)
+	assert.Error(t, err)
+	assertBiMapEqual(t, m, []string{"one", "two", "three"}, []int{1, 20, 3})
+
+	require.NoError(t, m.MoveToFront("three"))
+	require.NoError(t, m.MoveAfter("one", "two"))
+	assertBiMapEqual(t, m, []string{"three", "two", "one"}, []int{3, 20, 1})
+	require.NoError(t, m.MoveToBack("three"))
+	require.NoError(t, m.MoveBefore("one", "two"))
+	assertBiMapEqual(t, m, []string{"one", "two", "three"}, []int{1, 20, 3})
+
+	value, present = m.Delete("one")
+	assert.True(t, present)
+	assert.Equal(t, 1, value)
+	_, present = m.GetKey(1)
+	assert.False(t, present)
+
+	key, present = m.DeleteValue(20)
+	assert.True(t, present)
+	assert.Equal(t, "two", key)
+	_, present = m.Get("two")
+	assert.False(t, present)
+	_, present = m.DeleteValue(20)
+	assert.False(t, present)
+
+	assertBiMapEqual(t, m, []string{"three"}, []int{3})
+}
+
+func TestBiMapReplaceOnCollision(t *testing.T) {
+	m := NewBiMap(WithCollisionPolicy[string, int](ReplaceOnCollision))
+	for i, key := range []string{"a", "b", "c"} {
+		_, _, err := m.Set(key, i)
+		require.NoError(t, err)
+	}
+
+	// the new key replaces the old one
+	_, _, err := m.Set("d", 1)
+	require.NoError(t, err)
+	assertBiMapEqual(t, m, []string{"a", "c", "d"}, []int{0, 2, 1})
+
+	// an existing key stealing another's value
+	previous, present, err := m.Set("a", 2)
+	require.NoError(t, err)
+	assert.True(t, present)
+	assert.Equal(t, 0, previous)
+	assertBiMapEqual(t, m, []string{"a", "d"}, []int{2, 1})
+	_, present = m.GetKey(0)
+	assert.False(t, present)
+}
+
+func TestBiMapMarshalling(t *testing.T) {
+	m := NewBiMap[string, int]()
+	_, _, _ = m.Set("b", 2)
+	_, _, _ = m.Set("a", 1)
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.Equal(t, `{"b":2,"a":1}`, string(data))
+
+		var decoded BiMap[string, int]
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assertBiMapEqual(t, &decoded, []string{"b", "a"}, []int{2, 1})
+
+		var collision BiMap[string, int]
+		err = json.Unmarshal([]byte(`{"a":1,"b":1}`), &collision)
+		assert.Equal(t, &ValueCollisionError[string, int]{Key: "b", Value: 1, ExistingKey: "a"}, err)
+
+		replacing := NewBiMap(WithCollisionPolicy[string, int](ReplaceOnCollision))
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":1}`), replacing))
+		assertBiMapEqual(t, replacing, []string{"b"}, []int{1})
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		data, err := yaml.Marshal(m)
+		require.NoError(t, err)
+		assert.Equal(t, "b: 2\na: 1\n", string(data))
+
+		var decoded BiMap[string, int]
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		assertBiMapEqual(t, &decoded, []string{"b", "a"}, []int{2, 1})
+
+		var collision BiMap[string, int]
+		assert.Error(t, yaml.Unmarshal([]byte("a: 1\nb: 1\n"), &collision))
+	})
+}
+
+func assertBiMapEqual[K, V comparable](t *testing.T, m *BiMap[K, V], expectedKeys []K, expectedValues []V) {
+	t.Helper()
+
+	assertOrderedPairsEqual(t, m.om, expectedKeys, expectedValues)
+	assert.Len(t, m.keys, len(expectedKeys))
+	for i, value := range expectedValues {
+		key, present := m.GetKey(value)
+		assert.True(t, present)
+		assert.Equal(t, expectedKeys[i], key)
+	}
+}
@@ -0,0 +1,51 @@
+package orderedmap
+
+// WithCheckedIterators makes the map fail fast when it's misused while being iterated over,
+// instead of silently yielding incomplete or out-of-order results. Specifically:
+//   - the map's iterators panic if the map's structure (i.e. its keys or their order) is modified
+//     during the iteration. PairsFromOldest and PairsFromNewest still allow deleting the current pair.
+//   - Pair.Next and Pair.Prev panic when called on a pair that has been deleted from the map.
+//
+// Note that with the AccessOrder policy, reading a key moves it, and is then a structural modification.
+// The checks add a small overhead to iterating, and are meant to help catch bugs.
+func WithCheckedIterators[K comparable, V any]() InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.checked = true
+	}
+}
+
+const (
+	concurrentModificationMessage = `orderedmap: the map was structurally modified during an iteration`
+	detachedPairMessage           = `orderedmap: Next or Prev called on a pair that has been deleted from its map`
+)
+
+// IsDetached returns true iff the pair is not, or no longer, in a map; e.g. because it's been deleted,
+// or the map's been cleared.
+func (p *Pair[K, V]) IsDetached() bool {
+	return !p.attached
+}
+
+// checkAttached is called by Next and Prev on every step of an iteration: it must only read the
+// pair's own fields, so that maps not created WithCheckedIterators pay no overhead.
+func (p *Pair[K, V]) checkAttached() {
+	if p.checked && !p.attached {
+		panic(detachedPairMessage)
+	}
+}
+
+// checkedYield wraps yield so that it panics if the map is modified when yield continues the iteration.
+func (om *OrderedMap[K, V]) checkedYield(yield func(*Pair[K, V]) bool) func(*Pair[K, V]) bool {
+	if om == nil || !om.checked {
+		return yield
+	}
+	return func(pair *Pair[K, V]) bool {
+		modCount := om.modCount
+		if !yield(pair) {
+			return false
+		}
+		if om.modCount != modCount {
+			panic(concurrentModificationMessage)
+		}
+		return true
+	}
+}
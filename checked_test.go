@@ -0,0 +1,123 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDetached(t *testing.T) {
+	om := New[int, int]()
+	om.Set(1, 1)
+	om.Set(2, 2)
+
+	pair := om.GetPair(1)
+	assert.False(t, pair.IsDetached())
+
+	om.Delete(1)
+	assert.True(t, pair.IsDetached())
+
+	// re-inserting the same key creates a new pair
+	om.Set(1, 1)
+	assert.True(t, pair.IsDetached())
+	assert.False(t, om.GetPair(1).IsDetached())
+
+	pair = om.GetPair(2)
+	om.initialize(0, false)
+	assert.True(t, pair.IsDetached())
+
+	assert.True(t, (&Pair[int, int]{Key: 1}).IsDetached())
+}
+
+func TestCheckedIterators(t *testing.T) {
+	for _, mode := range storageModes[int, int]() {
+		t.Run(mode.name, func(t *testing.T) {
+			newMap := func() *OrderedMap[int, int] {
+				om := New[int, int](append(mode.options, WithCheckedIterators[int, int]())...)
+				for i := 0; i < 5; i++ {
+					om.Set(i, i)
+				}
+				return om
+			}
+
+			t.Run("Next on a deleted pair", func(t *testing.T) {
+				om := newMap()
+				pair := om.GetPair(2)
+				om.Delete(2)
+
+				assert.PanicsWithValue(t, detachedPairMessage, func() { pair.Next() })
+				assert.PanicsWithValue(t, detachedPairMessage, func() { pair.Prev() })
+			})
+
+			t.Run("modifying while iterating", func(t *testing.T) {
+				for name, modify := range map[string]func(om *OrderedMap[int, int]){
+					"set":    func(om *OrderedMap[int, int]) { om.Set(28, 28) },
+					"delete": func(om *OrderedMap[int, int]) { om.Delete(4) },
+					"move":   func(om *OrderedMap[int, int]) { _ = om.MoveToFront(3) },
+				} {
+					t.Run(name, func(t *testing.T) {
+						om := newMap()
+						assert.PanicsWithValue(t, concurrentModificationMessage, func() {
+							for range om.FromOldest() {
+								modify(om)
+							}
+						})
+
+						om = newMap()
+						assert.PanicsWithValue(t, concurrentModificationMessage, func() {
+							for range om.ValuesFromNewest() {
+								modify(om)
+							}
+						})
+
+						om = newMap()
+						assert.PanicsWithValue(t, concurrentModificationMessage, func() {
+							for pair := range om.PairsFromOldest() {
+								if pair.Key == 0 {
+									modify(om)
+								}
+							}
+						})
+					})
+				}
+			})
+
+			t.Run("allowed changes", func(t *testing.T) {
+				om := newMap()
+
+				// updating values isn't a structural change
+				for k, v := range om.FromOldest() {
+					om.Set(k, v*10)
+				}
+
+				// modifying before breaking out of the loop is fine
+				for k := range om.FromOldest() {
+					om.Delete(k)
+					break
+				}
+
+				// deleting the current pair is fine with pair iterators
+				for pair := range om.PairsFromNewest() {
+					if pair.Key%2 == 0 {
+						om.Delete(pair.Key)
+					}
+				}
+				assertOrderedPairsEqual(t, om, []int{1, 3}, []int{10, 30})
+
+				assert.True(t, om.Clone().checked)
+			})
+		})
+	}
+}
+
+func TestCheckedIteratorsWithAccessOrder(t *testing.T) {
+	om := New[int, int](WithCheckedIterators[int, int](), WithOrderingPolicy[int, int](AccessOrder))
+	om.Set(1, 1)
+	om.Set(2, 2)
+
+	assert.PanicsWithValue(t, concurrentModificationMessage, func() {
+		for k := range om.KeysFromOldest() {
+			om.Get(k)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package orderedmap
+
+// Clone returns a shallow copy of the map, i.e. the values are copied with a plain assignment.
+// The copy has the same configuration as the original map, e.g. whether it escapes HTML when
+// marshalling to JSON, its OrderingPolicy, etc.
+// Cloning a nil map returns nil.
+func (om *OrderedMap[K, V]) Clone() *OrderedMap[K, V] {
+	return om.CloneFunc(func(value V) V { return value })
+}
+
+// CloneFunc is the same as Clone, except that values are copied using copyValue, allowing
+// for deep copies.
+func (om *OrderedMap[K, V]) CloneFunc(copyValue func(V) V) *OrderedMap[K, V] {
+	if om == nil {
+		return nil
+	}
+
+	clone := om.emptyClone(om.Len())
+	for pair := range om.pairsFromOldest() {
+		clone.insert(pair.Key, copyValue(pair.Value))
+	}
+
+	return clone
+}
+
+// emptyClone returns a new, empty map with the same configuration as om.
+func (om *OrderedMap[K, V]) emptyClone(capacity int) *OrderedMap[K, V] {
+	clone := &OrderedMap[K, V]{
+		orderingPolicy:     om.orderingPolicy,
+		maxTombstoneRatio:  om.maxTombstoneRatio,
+		checked:            om.checked,
+		nestedJSONDecoding: om.nestedJSONDecoding,
+		duplicateKeyPolicy: om.duplicateKeyPolicy,
+		jsonIndentation:    om.jsonIndentation,
+	}
+	if om.index != nil {
+		clone.index = &orderIndex[K, V]{}
+	}
+	clone.initialize(capacity, om.disableHTMLEscape)
+
+	return clone
+}
+
+// Equal reports whether two maps contain the same key-value pairs, in the same order.
+// Values are compared using ==. A nil map is equal to an empty one.
+func Equal[K, V comparable](om1, om2 *OrderedMap[K, V]) bool {
+	return EqualFunc(om1, om2, func(v1, v2 V) bool { return v1 == v2 })
+}
+
+// EqualFunc is like Equal, but compares values using eq.
+func EqualFunc[K comparable, V1, V2 any](om1 *OrderedMap[K, V1], om2 *OrderedMap[K, V2], eq func(V1, V2) bool) bool {
+	if om1.Len() != om2.Len() {
+		return false
+	}
+
+	for pair1, pair2 := om1.Oldest(), om2.Oldest(); pair1 != nil; pair1, pair2 = pair1.Next(), pair2.Next() {
+		if pair1.Key != pair2.Key || !eq(pair1.Value, pair2.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualUnordered reports whether two maps contain the same key-value pairs, regardless of
+// their order. Values are compared using ==. A nil map is equal to an empty one.
+func EqualUnordered[K, V comparable](om1, om2 *OrderedMap[K, V]) bool {
+	if om1.Len() != om2.Len() {
+		return false
+	}
+
+	for pair1 := om1.Oldest(); pair1 != nil; pair1 = pair1.Next() {
+		if pair2 := om2.GetPair(pair1.Key); pair2 == nil || pair1.Value != pair2.Value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,134 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	om := New[string, []int]()
+	om.Set("foo", []int{1})
+	om.Set("bar", []int{2, 3})
+	om.Set("baz", nil)
+
+	t.Run("shallow", func(t *testing.T) {
+		clone := om.Clone()
+		assertOrderedPairsEqual(t, clone,
+			[]string{"foo", "bar", "baz"},
+			[][]int{{1}, {2, 3}, nil})
+
+		// the clone is independent from the original map...
+		clone.Set("qux", []int{4})
+		clone.Delete("foo")
+		require.NoError(t, clone.MoveToFront("baz"))
+		assertOrderedPairsEqual(t, om,
+			[]string{"foo", "bar", "baz"},
+			[][]int{{1}, {2, 3}, nil})
+
+		// ... but shares its values
+		clone.Value("bar")[0] = 20
+		assert.Equal(t, []int{20, 3}, om.Value("bar"))
+		om.Value("bar")[0] = 2
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		clone := om.CloneFunc(func(value []int) []int {
+			return append([]int(nil), value...)
+		})
+		assertOrderedPairsEqual(t, clone,
+			[]string{"foo", "bar", "baz"},
+			[][]int{{1}, {2, 3}, nil})
+
+		clone.Value("bar")[0] = 20
+		assert.Equal(t, []int{2, 3}, om.Value("bar"))
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		var nilMap *OrderedMap[string, int]
+		assert.Nil(t, nilMap.Clone())
+	})
+}
+
+func TestCloneKeepsConfiguration(t *testing.T) {
+	om := New[string, string](
+		WithDisableHTMLEscape[string, string](),
+		WithOrderingPolicy[string, string](AccessOrder),
+		WithIndexing[string, string](),
+		WithCompactStorage[string, string](0.3),
+	)
+	om.Set("a", "<b>")
+	om.Set("c", "d")
+
+	clone := om.Clone()
+
+	data, err := clone.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"<b>","c":"d"}`, string(data))
+
+	clone.Get("a")
+	assertOrderedPairsEqual(t, clone, []string{"c", "a"}, []string{"d", "<b>"})
+
+	assertIndexConsistent(t, clone)
+	assert.Equal(t, 0.3, clone.list.(*compactList[string, string]).maxTombstoneRatio)
+}
+
+func TestEqual(t *testing.T) {
+	om1 := New[string, int]()
+	om1.Set("a", 1)
+	om1.Set("b", 2)
+	om1.Set("c", 3)
+
+	om2 := om1.Clone()
+	assert.True(t, Equal(om1, om2))
+	assert.True(t, EqualUnordered(om1, om2))
+
+	// same pairs, different order
+	require.NoError(t, om2.MoveToFront("c"))
+	assert.False(t, Equal(om1, om2))
+	assert.True(t, EqualUnordered(om1, om2))
+
+	// different values
+	om2 = om1.Clone()
+	om2.Set("b", 20)
+	assert.False(t, Equal(om1, om2))
+	assert.False(t, EqualUnordered(om1, om2))
+
+	// different keys
+	om2 = om1.Clone()
+	om2.Delete("b")
+	om2.Set("d", 2)
+	assert.False(t, EqualUnordered(om1, om2))
+
+	// different lengths
+	om2.Delete("d")
+	assert.False(t, Equal(om1, om2))
+	assert.False(t, EqualUnordered(om1, om2))
+
+	// nil and empty maps
+	var nilMap *OrderedMap[string, int]
+	assert.True(t, Equal(nilMap, New[string, int]()))
+	assert.True(t, EqualUnordered(New[string, int](), nilMap))
+	assert.False(t, Equal(nilMap, om1))
+}
+
+func TestEqualFunc(t *testing.T) {
+	om1 := New[int, []int]()
+	om1.Set(1, []int{1})
+	om1.Set(2, []int{2, 3})
+
+	om2 := New[int, int]()
+	om2.Set(1, 1)
+	om2.Set(2, 2)
+
+	sameLength := func(v1 []int, v2 int) bool { return len(v1) == v2 }
+	assert.True(t, EqualFunc(om1, om2, sameLength))
+
+	require.NoError(t, om2.MoveToFront(2))
+	assert.False(t, EqualFunc(om1, om2, sameLength))
+
+	om2.Set(1, 2)
+	require.NoError(t, om2.MoveToFront(1))
+	assert.False(t, EqualFunc(om1, om2, sameLength))
+}
@@ -0,0 +1,241 @@
+package orderedmap
+
+// defaultMaxTombstoneRatio is used by WithCompactStorage when given an invalid ratio.
+const defaultMaxTombstoneRatio = 0.5
+
+// compactionMinSize is the minimum number of slots a compact list must have before being compacted;
+// it avoids repeatedly compacting tiny maps.
+const compactionMinSize = 32
+
+// WithCompactStorage makes the map store its pairs' order in a contiguous slice rather than
+// in a linked list, which makes iterating over the map more cache-friendly. That's best suited
+// for large maps that are mostly appended to, and iterated over far more often than they're modified.
+//
+// Deleting a pair leaves a tombstone in the slice; once tombstones make up more than
+// maxTombstoneRatio of the slice, it is compacted. maxTombstoneRatio must be between 0 and 1,
+// otherwise it defaults to 0.5.
+//
+// Appending pairs, deleting pairs and moving them to the back of the map take amortized
+// constant time, but moving pairs anywhere else takes linear time in the worst case.
+// Pair pointers remain valid across compactions.
+func WithCompactStorage[K comparable, V any](maxTombstoneRatio float64) InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		if maxTombstoneRatio <= 0 || maxTombstoneRatio >= 1 {
+			maxTombstoneRatio = defaultMaxTombstoneRatio
+		}
+		c.maxTombstoneRatio = maxTombstoneRatio
+	}
+}
+
+// compactList is a pairStore keeping pairs in a slice, where nil entries are tombstones
+// left by removed pairs.
+// Each pair knows its position in the slice, which is updated whenever the slice is reorganized.
+// The slice never ends with a tombstone, and head is always the position of the first live pair
+// (or 0 if there are none), so that both ends of the list can be found in constant time.
+type compactList[K comparable, V any] struct {
+	entries           []*Pair[K, V]
+	head              int
+	len               int
+	maxTombstoneRatio float64
+}
+
+func newCompactList[K comparable, V any](capacity int, maxTombstoneRatio float64) *compactList[K, V] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &compactList[K, V]{
+		entries:           make([]*Pair[K, V], 0, capacity),
+		maxTombstoneRatio: maxTombstoneRatio,
+	}
+}
+
+func (l *compactList[K, V]) Front() *Pair[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.entries[l.head]
+}
+
+func (l *compactList[K, V]) Back() *Pair[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.entries[len(l.entries)-1]
+}
+
+func (l *compactList[K, V]) Len() int {
+	return l.len
+}
+
+func (l *compactList[K, V]) PushBack(pair *Pair[K, V]) {
+	l.insertAt(pair, len(l.entries))
+}
+
+func (l *compactList[K, V]) Remove(pair *Pair[K, V]) {
+	l.entries[pair.position] = nil
+	pair.compact = nil
+	l.len--
+
+	switch {
+	case l.len == 0:
+		l.entries = l.entries[:0]
+		l.head = 0
+		return
+	case pair.position == l.head:
+		for l.entries[l.head] == nil {
+			l.head++
+		}
+	case pair.position == len(l.entries)-1:
+		last := len(l.entries) - 2
+		for l.entries[last] == nil {
+			last--
+		}
+		clear(l.entries[last+1:])
+		l.entries = l.entries[:last+1]
+	}
+
+	tombstones := len(l.entries) - l.len
+	if len(l.entries) >= compactionMinSize && float64(tombstones) > l.maxTombstoneRatio*float64(len(l.entries)) {
+		l.compact()
+	}
+}
+
+func (l *compactList[K, V]) MoveAfter(pair, mark *Pair[K, V]) {
+	if pair == mark {
+		return
+	}
+	l.Remove(pair)
+	l.insertAt(pair, mark.position+1)
+}
+
+func (l *compactList[K, V]) MoveBefore(pair, mark *Pair[K, V]) {
+	if pair == mark {
+		return
+	}
+	l.Remove(pair)
+	l.insertAt(pair, mark.position)
+}
+
+func (l *compactList[K, V]) MoveToBack(pair *Pair[K, V]) {
+	if l.Back() == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAt(pair, len(l.entries))
+}
+
+func (l *compactList[K, V]) MoveToFront(pair *Pair[K, V]) {
+	if l.Front() == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAt(pair, l.head)
+}
+
+// insertAt inserts the pair, which must not be in the list already, just before the entry
+// currently at position, or at the back if position is the length of the slice.
+// It re-uses a tombstone right before that position if possible, otherwise it shifts entries
+// to the right up to the next tombstone.
+func (l *compactList[K, V]) insertAt(pair *Pair[K, V], position int) {
+	switch {
+	case l.len == 0:
+		position = len(l.entries)
+		l.entries = append(l.entries, nil)
+		l.head = position
+	case position > 0 && l.entries[position-1] == nil:
+		position--
+		l.head = min(l.head, position)
+	default:
+		tombstone := position
+		for tombstone < len(l.entries) && l.entries[tombstone] != nil {
+			tombstone++
+		}
+		if tombstone == len(l.entries) {
+			l.entries = append(l.entries, nil)
+		}
+		for i := tombstone; i > position; i-- {
+			l.entries[i] = l.entries[i-1]
+			l.entries[i].position = i
+		}
+	}
+
+	l.entries[position] = pair
+	pair.position = position
+	pair.compact = l
+	l.len++
+}
+
+// compact removes all tombstones.
+func (l *compactList[K, V]) compact() {
+	live := 0
+	for _, pair := range l.entries {
+		if pair != nil {
+			l.entries[live] = pair
+			pair.position = live
+			live++
+		}
+	}
+	clear(l.entries[live:])
+	l.entries = l.entries[:live]
+	l.head = 0
+}
+
+func (l *compactList[K, V]) next(pair *Pair[K, V]) *Pair[K, V] {
+	for i := pair.position + 1; i < len(l.entries); i++ {
+		if l.entries[i] != nil {
+			return l.entries[i]
+		}
+	}
+	return nil
+}
+
+func (l *compactList[K, V]) prev(pair *Pair[K, V]) *Pair[K, V] {
+	for i := pair.position - 1; i >= l.head; i-- {
+		if l.entries[i] != nil {
+			return l.entries[i]
+		}
+	}
+	return nil
+}
+
+// forward calls yield on each pair from the front of the list, until it returns false.
+// Like walking the list with next, it supports the list being modified by yield, and stops
+// if the last yielded pair is removed from the list.
+func (l *compactList[K, V]) forward(yield func(*Pair[K, V]) bool) {
+	for i := l.head; i < len(l.entries); i++ {
+		pair := l.entries[i]
+		if pair == nil {
+			continue
+		}
+		if !yield(pair) {
+			return
+		}
+		if i >= len(l.entries) || l.entries[i] != pair {
+			// the list was re-organized
+			if pair.compact != l {
+				return
+			}
+			i = pair.position
+		}
+	}
+}
+
+// backward is the same as forward, starting from the back of the list.
+func (l *compactList[K, V]) backward(yield func(*Pair[K, V]) bool) {
+	for i := len(l.entries) - 1; i >= l.head; i-- {
+		pair := l.entries[i]
+		if pair == nil {
+			continue
+		}
+		if !yield(pair) {
+			return
+		}
+		if i >= len(l.entries) || l.entries[i] != pair {
+			// the list was re-organized
+			if pair.compact != l {
+				return
+			}
+			i = pair.position
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactStorage(t *testing.T) {
+	om := New[int, any](WithCompactStorage[int, any](0.5))
+	om.Set(1, "bar")
+	om.Set(2, 28)
+	om.Set(3, 100)
+	om.Set(4, "baz")
+	om.Set(5, "28")
+	om.Set(6, "100")
+	om.Set(7, "baz")
+	om.Set(8, "baz")
+
+	om.Delete(3)
+	om.Delete(1)
+	assertOrderedPairsEqual(t, om,
+		[]int{2, 4, 5, 6, 7, 8},
+		[]any{28, "baz", "28", "100", "baz", "baz"})
+
+	require.NoError(t, om.MoveAfter(2, 4))
+	require.NoError(t, om.MoveBefore(8, 5))
+	require.NoError(t, om.MoveToFront(7))
+	require.NoError(t, om.MoveToBack(4))
+	assertOrderedPairsEqual(t, om,
+		[]int{7, 2, 8, 5, 6, 4},
+		[]any{"baz", 28, "baz", "28", "100", "baz"})
+
+	pair := om.GetPair(5)
+	assert.Equal(t, 8, pair.Prev().Key)
+	assert.Equal(t, 6, pair.Next().Key)
+
+	om.Delete(5)
+	assert.Nil(t, pair.Next())
+	assert.Nil(t, pair.Prev())
+
+	for _, key := range []int{7, 2, 8, 6, 4} {
+		om.Delete(key)
+	}
+	assertLenEqual(t, om, 0)
+	assert.Nil(t, om.Oldest())
+	assert.Nil(t, om.Newest())
+
+	om.Set(9, "foo")
+	assertOrderedPairsEqual(t, om, []int{9}, []any{"foo"})
+}
+
+func TestCompactStorageCompaction(t *testing.T) {
+	n := 1000
+	om := New[int, int](WithCompactStorage[int, int](0.25))
+	for i := 0; i < n; i++ {
+		om.Set(i, i)
+	}
+	pairs := make([]*Pair[int, int], n)
+	for i := 0; i < n; i++ {
+		pairs[i] = om.GetPair(i)
+	}
+
+	compact, ok := om.list.(*compactList[int, int])
+	require.True(t, ok)
+
+	// delete every other pair from the middle of the map
+	var expectedKeys []int
+	for i := 0; i < n; i++ {
+		if i%2 == 1 && i > 10 && i < n-10 {
+			om.Delete(i)
+			assert.LessOrEqual(t, float64(len(compact.entries)-compact.len), 0.25*float64(len(compact.entries)))
+		} else {
+			expectedKeys = append(expectedKeys, i)
+		}
+	}
+	assert.Less(t, len(compact.entries), n)
+	assertOrderedPairsEqual(t, om, expectedKeys, expectedKeys)
+
+	// pointers remain valid across compactions
+	for _, key := range expectedKeys {
+		assert.Same(t, pairs[key], om.GetPair(key))
+	}
+}
+
+func TestCompactStorageDefaultRatio(t *testing.T) {
+	for _, ratio := range []float64{-1, 0, 1, 2} {
+		om := New[int, int](WithCompactStorage[int, int](ratio))
+		assert.Equal(t, defaultMaxTombstoneRatio, om.list.(*compactList[int, int]).maxTombstoneRatio)
+	}
+}
+
+func TestCompactStorageConsistency(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			options := []any{WithCompactStorage[int, int](0.3)}
+			if indexed {
+				options = append(options, WithIndexing[int, int]())
+			}
+			compact := New[int, int](options...)
+			plain := New[int, int]()
+
+			rng := rand.New(rand.NewPCG(12, 28)) //nolint:gosec
+			for i := 0; i < 5000; i++ {
+				key := rng.IntN(100)
+				switch op := rng.IntN(8); op {
+				case 0, 1:
+					compact.Set(key, i)
+					plain.Set(key, i)
+				case 2, 3:
+					compact.Delete(key)
+					plain.Delete(key)
+				case 4:
+					markKey := rng.IntN(100)
+					assert.Equal(t, plain.MoveAfter(key, markKey), compact.MoveAfter(key, markKey))
+				case 5:
+					markKey := rng.IntN(100)
+					assert.Equal(t, plain.MoveBefore(key, markKey), compact.MoveBefore(key, markKey))
+				case 6:
+					assert.Equal(t, plain.MoveToFront(key), compact.MoveToFront(key))
+				case 7:
+					assert.Equal(t, plain.MoveToBack(key), compact.MoveToBack(key))
+				}
+			}
+
+			var keys []int
+			var values []int
+			for k, v := range plain.FromOldest() {
+				keys = append(keys, k)
+				values = append(values, v)
+			}
+			assertOrderedPairsEqual(t, compact, keys, values)
+			assertLenEqual(t, compact, plain.Len())
+			if indexed {
+				assertIndexConsistent(t, compact)
+			}
+		})
+	}
+}
+
+var benchmarkSink int
+
+// the pairs' order no longer matches the order in which they were allocated, as in a long-lived map
+func BenchmarkIterateShuffled(b *testing.B) {
+	for _, testCase := range []struct {
+		name    string
+		options []any
+	}{
+		{name: "linked list"},
+		{name: "compact storage", options: []any{WithCompactStorage[int, int](0)}},
+	} {
+		b.Run(testCase.name, func(b *testing.B) {
+			n := 100 * benchmarkMapSize
+			om := New[int, int](testCase.options...)
+			for j := 0; j < n; j++ {
+				om.Set(j, j)
+			}
+			rng := rand.New(rand.NewPCG(28, 12)) //nolint:gosec
+			for _, j := range rng.Perm(n) {
+				_ = om.MoveToBack(j)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sum := 0
+				for _, v := range om.FromOldest() {
+					sum += v
+				}
+				benchmarkSink = sum
+			}
+		})
+	}
+}
+
+func TestCompactStorageIteratorsWithModifications(t *testing.T) {
+	for _, options := range [][]any{nil, {WithCompactStorage[int, int](0.1)}} {
+		om := New[int, int](options...)
+		for i := 0; i < 100; i++ {
+			om.Set(i, i)
+		}
+
+		var keys []int
+		for k := range om.FromOldest() {
+			keys = append(keys, k)
+			// deleting pairs before the current one triggers compactions
+			om.Delete(k - 1)
+			om.Delete(k + 1)
+		}
+		expectedKeys := make([]int, 0, 50)
+		for i := 0; i < 100; i += 2 {
+			expectedKeys = append(expectedKeys, i)
+		}
+		assert.Equal(t, expectedKeys, keys)
+		assertOrderedPairsEqual(t, om, expectedKeys, expectedKeys)
+
+		keys = nil
+		for k := range om.KeysFromNewest() {
+			keys = append(keys, k)
+			if k == 50 {
+				// deleting the current pair stops the iteration, as with Prev
+				om.Delete(k)
+			}
+		}
+		assert.Equal(t, []int{98, 96, 94, 92, 90, 88, 86, 84, 82, 80, 78, 76, 74, 72, 70, 68, 66, 64, 62, 60, 58, 56, 54, 52, 50}, keys)
+	}
+}
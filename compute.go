@@ -0,0 +1,78 @@
+package orderedmap
+
+// GetOrSet returns the value associated with key if present, like Get. Otherwise, it sets
+// the key-value pair at the back of the map, and returns value.
+// The boolean it returns says whether the key was already present.
+func (om *OrderedMap[K, V]) GetOrSet(key K, value V) (actual V, present bool) {
+	return om.GetOrSetFunc(key, func() V { return value })
+}
+
+// GetOrSetFunc is the same as GetOrSet, except that the value to set is only computed,
+// by calling newValue, if key is not present.
+func (om *OrderedMap[K, V]) GetOrSetFunc(key K, newValue func() V) (actual V, present bool) {
+	if pair, present := om.pairs[key]; present {
+		om.read(pair)
+		return pair.Value, true
+	}
+
+	actual = newValue()
+	om.insert(key, actual)
+	return actual, false
+}
+
+// Update replaces the value associated with key with the result of calling update on it,
+// and returns true; or returns false and leaves the map untouched if key is not present.
+// As with Set, whether the pair is moved depends on the map's OrderingPolicy.
+// update must not modify the map.
+func (om *OrderedMap[K, V]) Update(key K, update func(V) V) bool {
+	pair, present := om.pairs[key]
+	if present {
+		om.write(pair, update(pair.Value))
+	}
+	return present
+}
+
+// Upsert sets key's value to the result of calling upsert with its current value, or with V's
+// zero value and false if key is not present, and returns the new value.
+// New keys are inserted at the back of the map; as with Set, whether existing ones are moved
+// depends on the map's OrderingPolicy.
+func (om *OrderedMap[K, V]) Upsert(key K, upsert func(old V, present bool) V) V {
+	value, _ := om.Compute(key, func(old V, present bool) (V, bool) {
+		return upsert(old, present), true
+	})
+	return value
+}
+
+// Compute calls compute with the value associated with key and true if key is present,
+// or with V's zero value and false otherwise; then:
+//   - if compute returns true, sets key's value to the value it returned: new keys are inserted
+//     at the back of the map, and as with Set, whether existing ones are moved depends on the
+//     map's OrderingPolicy.
+//   - otherwise, deletes key from the map if present.
+//
+// It returns the value now associated with key, and whether key is present in the map after the call.
+// compute must not modify the map.
+func (om *OrderedMap[K, V]) Compute(key K, compute func(old V, present bool) (value V, keep bool)) (V, bool) {
+	pair, present := om.pairs[key]
+
+	var old V
+	if present {
+		old = pair.Value
+	}
+
+	value, keep := compute(old, present)
+	switch {
+	case !keep:
+		if present {
+			om.remove(pair)
+		}
+		var zero V
+		return zero, false
+	case present:
+		om.write(pair, value)
+	default:
+		om.insert(key, value)
+	}
+
+	return value, true
+}
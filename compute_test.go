@@ -0,0 +1,124 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrSet(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+
+	actual, present := om.GetOrSet("a", 28)
+	assert.True(t, present)
+	assert.Equal(t, 1, actual)
+
+	actual, present = om.GetOrSet("b", 2)
+	assert.False(t, present)
+	assert.Equal(t, 2, actual)
+
+	calls := 0
+	newValue := func() int {
+		calls++
+		return 3
+	}
+	actual, present = om.GetOrSetFunc("b", newValue)
+	assert.True(t, present)
+	assert.Equal(t, 2, actual)
+	assert.Equal(t, 0, calls)
+
+	actual, present = om.GetOrSetFunc("c", newValue)
+	assert.False(t, present)
+	assert.Equal(t, 3, actual)
+	assert.Equal(t, 1, calls)
+
+	assertOrderedPairsEqual(t, om, []string{"a", "b", "c"}, []int{1, 2, 3})
+}
+
+func TestUpdate(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	double := func(v int) int { return 2 * v }
+	assert.True(t, om.Update("a", double))
+	assert.False(t, om.Update("i dont exist", double))
+	assertOrderedPairsEqual(t, om, []string{"a", "b"}, []int{2, 2})
+}
+
+func TestCompute(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	increment := func(old int, present bool) (int, bool) {
+		if !present {
+			return 100, true
+		}
+		return old + 1, true
+	}
+
+	value, present := om.Compute("a", increment)
+	assert.True(t, present)
+	assert.Equal(t, 2, value)
+
+	value, present = om.Compute("c", increment)
+	assert.True(t, present)
+	assert.Equal(t, 100, value)
+
+	assertOrderedPairsEqual(t, om, []string{"a", "b", "c"}, []int{2, 2, 100})
+
+	remove := func(old int, present bool) (int, bool) {
+		assert.True(t, present)
+		assert.Equal(t, 2, old)
+		return 28, false
+	}
+	value, present = om.Compute("b", remove)
+	assert.False(t, present)
+	assert.Equal(t, 0, value)
+
+	value, present = om.Compute("i dont exist", func(old int, present bool) (int, bool) {
+		assert.False(t, present)
+		assert.Equal(t, 0, old)
+		return 28, false
+	})
+	assert.False(t, present)
+	assert.Equal(t, 0, value)
+
+	assertOrderedPairsEqual(t, om, []string{"a", "c"}, []int{2, 100})
+
+	assert.Equal(t, 3, om.Upsert("a", func(old int, present bool) int {
+		assert.True(t, present)
+		return old + 1
+	}))
+	assert.Equal(t, 12, om.Upsert("d", func(old int, present bool) int {
+		assert.False(t, present)
+		return 12
+	}))
+	assertOrderedPairsEqual(t, om, []string{"a", "c", "d"}, []int{3, 100, 12})
+}
+
+func TestComputeOrderingPolicies(t *testing.T) {
+	for _, testCase := range []struct {
+		policy        OrderingPolicy
+		expectedOrder []int
+	}{
+		{InsertionOrder, []int{1, 2, 3, 4, 5, 6}},
+		{WriteOrder, []int{1, 4, 3, 5, 2, 6}},
+		{AccessOrder, []int{4, 1, 3, 5, 2, 6}},
+	} {
+		om := New[int, int](WithOrderingPolicy[int, int](testCase.policy))
+		for i := 1; i <= 4; i++ {
+			om.Set(i, i)
+		}
+
+		om.GetOrSet(1, 0)
+		om.Update(3, func(v int) int { return v })
+		om.GetOrSet(5, 5)
+		om.Compute(2, func(v int, _ bool) (int, bool) { return v, true })
+		om.Compute(6, func(int, bool) (int, bool) { return 6, true })
+
+		assertOrderedPairsEqual(t, om, testCase.expectedOrder, testCase.expectedOrder)
+	}
+}
@@ -0,0 +1,199 @@
+package orderedmap
+
+import (
+	"iter"
+	"sync"
+)
+
+// ConcurrentOrderedMap is a thread-safe wrapper around an OrderedMap, with an API
+// mirroring that of `sync.Map`.
+//
+// Its zero value is not usable; instances must be created with NewConcurrent.
+// Iterators and Range operate on a snapshot of the map taken when the iteration starts,
+// so that they never observe a partially applied write, and so that it is safe to
+// call any of the map's methods from within the iteration.
+type ConcurrentOrderedMap[K comparable, V any] struct {
+	mutex sync.RWMutex
+	om    *OrderedMap[K, V]
+}
+
+// NewConcurrent creates a new ConcurrentOrderedMap. It accepts the same options as New.
+func NewConcurrent[K comparable, V any](options ...any) *ConcurrentOrderedMap[K, V] {
+	return &ConcurrentOrderedMap[K, V]{
+		om: New[K, V](options...),
+	}
+}
+
+// Load returns the value stored in the map for a key, or V's zero value if no value is present.
+// The boolean it returns says whether the key is present in the map.
+func (cm *ConcurrentOrderedMap[K, V]) Load(key K) (value V, ok bool) {
+	if cm.om.orderingPolicy == AccessOrder {
+		// with that policy, reads modify the map's order
+		cm.mutex.Lock()
+		defer cm.mutex.Unlock()
+	} else {
+		cm.mutex.RLock()
+		defer cm.mutex.RUnlock()
+	}
+
+	return cm.om.Get(key)
+}
+
+// Store sets the value for a key. As with OrderedMap.Set, whether updating an existing key
+// changes its position depends on the map's OrderingPolicy.
+func (cm *ConcurrentOrderedMap[K, V]) Store(key K, value V) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	cm.om.Set(key, value)
+}
+
+// LoadOrStore returns the existing value for the key if present. Otherwise, it stores
+// the given value at the back of the map, and returns it.
+// The loaded result is true if the value was loaded, false if stored.
+func (cm *ConcurrentOrderedMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	return cm.om.GetOrSet(key, value)
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (cm *ConcurrentOrderedMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	return cm.om.Delete(key)
+}
+
+// Delete deletes the value for a key.
+func (cm *ConcurrentOrderedMap[K, V]) Delete(key K) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	cm.om.Delete(key)
+}
+
+// Swap swaps the value for a key and returns the previous value if any.
+// The loaded result reports whether the key was present.
+func (cm *ConcurrentOrderedMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	return cm.om.Set(key, value)
+}
+
+// CompareAndSwap swaps the old and new values for key if the value stored in the map
+// is equal to old.
+// As with `sync.Map`, V values must be comparable at runtime, otherwise this panics.
+func (cm *ConcurrentOrderedMap[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) { //nolint:predeclared
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	pair := cm.om.GetPair(key)
+	if pair == nil || any(pair.Value) != any(old) {
+		return false
+	}
+	cm.om.write(pair, new)
+	return true
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// If there is no current value for key in the map, CompareAndDelete returns false.
+// As with `sync.Map`, V values must be comparable at runtime, otherwise this panics.
+func (cm *ConcurrentOrderedMap[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	pair := cm.om.GetPair(key)
+	if pair == nil || any(pair.Value) != any(old) {
+		return false
+	}
+	cm.om.Delete(key)
+	return true
+}
+
+// Update atomically replaces the value for key with the result of calling update on it;
+// see OrderedMap.Update. update is called with the map locked, and so must not call any
+// of the map's methods.
+func (cm *ConcurrentOrderedMap[K, V]) Update(key K, update func(V) V) bool {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	return cm.om.Update(key, update)
+}
+
+// Compute atomically inserts, updates or deletes the value for key depending on what
+// compute returns; see OrderedMap.Compute. compute is called with the map locked, and so
+// must not call any of the map's methods.
+func (cm *ConcurrentOrderedMap[K, V]) Compute(
+	key K,
+	compute func(old V, loaded bool) (value V, keep bool),
+) (value V, ok bool) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	return cm.om.Compute(key, compute)
+}
+
+// Clear deletes all the entries.
+func (cm *ConcurrentOrderedMap[K, V]) Clear() {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	cm.om.initialize(0, cm.om.disableHTMLEscape)
+}
+
+// Len returns the length of the map.
+func (cm *ConcurrentOrderedMap[K, V]) Len() int {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	return cm.om.Len()
+}
+
+// Range calls f sequentially for each key and value present in the map, from oldest to newest.
+// If f returns false, range stops the iteration.
+// Range iterates over a snapshot of the map, taken when Range is called; f may thus
+// call any method on the map.
+func (cm *ConcurrentOrderedMap[K, V]) Range(f func(key K, value V) bool) {
+	cm.FromOldest()(f)
+}
+
+// FromOldest returns an iterator over a snapshot of all the key-value pairs in the map,
+// starting from the oldest pair. The snapshot is taken when the iteration starts.
+func (cm *ConcurrentOrderedMap[K, V]) FromOldest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		snapshot := cm.snapshot()
+		for i := 0; i < len(snapshot); i++ {
+			if !yield(snapshot[i].Key, snapshot[i].Value) {
+				return
+			}
+		}
+	}
+}
+
+// FromNewest returns an iterator over a snapshot of all the key-value pairs in the map,
+// starting from the newest pair. The snapshot is taken when the iteration starts.
+func (cm *ConcurrentOrderedMap[K, V]) FromNewest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		snapshot := cm.snapshot()
+		for i := len(snapshot) - 1; i >= 0; i-- {
+			if !yield(snapshot[i].Key, snapshot[i].Value) {
+				return
+			}
+		}
+	}
+}
+
+func (cm *ConcurrentOrderedMap[K, V]) snapshot() []Pair[K, V] {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	snapshot := make([]Pair[K, V], 0, cm.om.Len())
+	for pair := cm.om.Oldest(); pair != nil; pair = pair.Next() {
+		snapshot = append(snapshot, Pair[K, V]{Key: pair.Key, Value: pair.Value})
+	}
+	return snapshot
+}
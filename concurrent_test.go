@@ -0,0 +1,234 @@
+package orderedmap
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentBasicFeatures(t *testing.T) {
+	cm := NewConcurrent[string, int]()
+
+	cm.Store("foo", 1)
+	cm.Store("bar", 2)
+	cm.Store("baz", 3)
+	assert.Equal(t, 3, cm.Len())
+
+	value, ok := cm.Load("bar")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	value, ok = cm.Load("i dont exist")
+	assert.False(t, ok)
+	assert.Equal(t, 0, value)
+
+	actual, loaded := cm.LoadOrStore("foo", 28)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+
+	actual, loaded = cm.LoadOrStore("qux", 28)
+	assert.False(t, loaded)
+	assert.Equal(t, 28, actual)
+
+	previous, loaded := cm.Swap("bar", 12)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, previous)
+
+	previous, loaded = cm.Swap("quux", 7)
+	assert.False(t, loaded)
+	assert.Equal(t, 0, previous)
+
+	assert.False(t, cm.CompareAndSwap("bar", 2, 100))
+	assert.True(t, cm.CompareAndSwap("bar", 12, 100))
+	assert.False(t, cm.CompareAndSwap("i dont exist", 0, 100))
+
+	assert.False(t, cm.CompareAndDelete("baz", 100))
+	assert.True(t, cm.CompareAndDelete("baz", 3))
+	assert.False(t, cm.CompareAndDelete("baz", 3))
+
+	value, loaded = cm.LoadAndDelete("qux")
+	assert.True(t, loaded)
+	assert.Equal(t, 28, value)
+
+	value, loaded = cm.LoadAndDelete("qux")
+	assert.False(t, loaded)
+	assert.Equal(t, 0, value)
+
+	cm.Delete("i dont exist")
+
+	assertConcurrentPairsEqual(t, cm,
+		[]string{"foo", "bar", "quux"},
+		[]int{1, 100, 7})
+
+	cm.Clear()
+	assert.Equal(t, 0, cm.Len())
+	cm.Store("foo", 1)
+	assertConcurrentPairsEqual(t, cm, []string{"foo"}, []int{1})
+}
+
+func TestConcurrentCompareAndSwapWithUncomparableValues(t *testing.T) {
+	cm := NewConcurrent[int, any]()
+	cm.Store(1, []int{1})
+
+	assert.Panics(t, func() {
+		cm.CompareAndSwap(1, []int{1}, []int{2})
+	})
+	assert.False(t, cm.CompareAndSwap(1, "foo", []int{2}))
+}
+
+func TestConcurrentRange(t *testing.T) {
+	cm := NewConcurrent[int, int]()
+	for i := 0; i < 10; i++ {
+		cm.Store(i, 2*i)
+	}
+
+	t.Run("stops when asked to", func(t *testing.T) {
+		var keys []int
+		cm.Range(func(key, value int) bool {
+			assert.Equal(t, 2*key, value)
+			keys = append(keys, key)
+			return key < 4
+		})
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, keys)
+	})
+
+	t.Run("iterates over a snapshot", func(t *testing.T) {
+		var keys []int
+		for key := range cm.FromNewest() {
+			keys = append(keys, key)
+			// neither deadlocks, nor changes what we iterate over
+			cm.Delete(key - 1)
+			cm.Store(100+key, key)
+		}
+		assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, keys)
+		assert.Equal(t, 11, cm.Len())
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	cm := NewConcurrent[string, int]()
+
+	goroutines, n := 8, 500
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < n; i++ {
+				key := fmt.Sprintf("%d-%d", g, i)
+				cm.Store(key, i)
+				cm.LoadOrStore(key, -1)
+				cm.CompareAndSwap(key, i, i+1)
+				if i%2 == 0 {
+					cm.LoadAndDelete(key)
+				}
+				cm.Range(func(string, int) bool { return false })
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, goroutines*n/2, cm.Len())
+	for key, value := range cm.FromOldest() {
+		var g, i int
+		_, err := fmt.Sscanf(key, "%d-%d", &g, &i)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, i%2)
+		assert.Equal(t, i+1, value)
+	}
+}
+
+func assertConcurrentPairsEqual[K comparable, V any](
+	t *testing.T, cm *ConcurrentOrderedMap[K, V], expectedKeys []K, expectedValues []V,
+) {
+	t.Helper()
+
+	var keys []K
+	var values []V
+	for k, v := range cm.FromOldest() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	assert.Equal(t, expectedKeys, keys)
+	assert.Equal(t, expectedValues, values)
+
+	keys, values = nil, nil
+	for k, v := range cm.FromNewest() {
+		keys = append([]K{k}, keys...)
+		values = append([]V{v}, values...)
+	}
+	assert.Equal(t, expectedKeys, keys)
+	assert.Equal(t, expectedValues, values)
+}
+
+func TestConcurrentLoadWithAccessOrder(t *testing.T) {
+	cm := NewConcurrent[int, int](WithOrderingPolicy[int, int](AccessOrder))
+	for i := 0; i < 10; i++ {
+		cm.Store(i, i)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for g := 0; g < 4; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, ok := cm.Load(i % 10)
+				assert.True(t, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, cm.Len())
+}
+
+func TestConcurrentCompareAndSwapWithOrderingPolicies(t *testing.T) {
+	for _, policy := range []OrderingPolicy{WriteOrder, AccessOrder} {
+		cm := NewConcurrent[string, int](WithOrderingPolicy[string, int](policy))
+		cm.Store("a", 1)
+		cm.Store("b", 2)
+
+		assert.False(t, cm.CompareAndSwap("a", 2, 5))
+		assertConcurrentPairsEqual(t, cm, []string{"a", "b"}, []int{1, 2})
+
+		assert.True(t, cm.CompareAndSwap("a", 1, 5))
+		assertConcurrentPairsEqual(t, cm, []string{"b", "a"}, []int{2, 5})
+	}
+}
+
+func TestConcurrentCompute(t *testing.T) {
+	cm := NewConcurrent[string, int]()
+
+	goroutines, n := 8, 500
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < n; i++ {
+				cm.Compute("counter", func(old int, _ bool) (int, bool) { return old + 1, true })
+				cm.Update("counter", func(v int) int { return v + 1 })
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	value, ok := cm.Load("counter")
+	assert.True(t, ok)
+	assert.Equal(t, 2*goroutines*n, value)
+
+	value, ok = cm.Compute("counter", func(int, bool) (int, bool) { return 0, false })
+	assert.False(t, ok)
+	assert.Equal(t, 0, value)
+	assert.False(t, cm.Update("counter", func(v int) int { return v + 1 }))
+	assert.Equal(t, 0, cm.Len())
+}
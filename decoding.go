@@ -0,0 +1,87 @@
+package orderedmap
+
+import "fmt"
+
+// DuplicateKeyPolicy determines how UnmarshalJSON, DecodeJSON and UnmarshalYAML handle keys
+// that appear several times in the same document.
+// Keys that were already present in the map prior to decoding are not considered duplicates:
+// their values are replaced the same way Set would, i.e. according to the map's OrderingPolicy.
+type DuplicateKeyPolicy int
+
+const (
+	// LastWinsKeepPosition keeps the last value, at the position of the key's first occurrence.
+	// This is the default policy.
+	LastWinsKeepPosition DuplicateKeyPolicy = iota
+	// LastWinsMoveToBack keeps the last value, at the position of the key's last occurrence.
+	LastWinsMoveToBack
+	// FirstWins keeps the first value, ignoring subsequent occurrences of the key.
+	FirstWins
+	// RejectDuplicates makes decoding fail with a DuplicateKeyError.
+	RejectDuplicates
+)
+
+// WithDuplicateKeyPolicy sets the map's DuplicateKeyPolicy; the default is LastWinsKeepPosition.
+// With WithNestedJSONDecoding, nested maps inherit that policy.
+func WithDuplicateKeyPolicy[K comparable, V any](policy DuplicateKeyPolicy) InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.duplicateKeyPolicy = policy
+	}
+}
+
+// DuplicateKeyError is returned when decoding a document containing duplicate keys, with the
+// RejectDuplicates policy.
+type DuplicateKeyError[K comparable] struct {
+	Key K
+	// for JSON documents, the offset of the duplicate key in the input
+	Offset int64
+	// for YAML documents, the position of the duplicate key
+	Line, Column int
+}
+
+func (e *DuplicateKeyError[K]) Error() string {
+	if e.Line != 0 {
+		return fmt.Sprintf("duplicate key %v at line %d, column %d", e.Key, e.Line, e.Column)
+	}
+	return fmt.Sprintf("duplicate key %v at offset %d", e.Key, e.Offset)
+}
+
+// seenKeys returns the set to pass to setDecoded when decoding a document. It's not needed when
+// the map is empty and the policy keeps the last value, as any key present in the map is then a
+// duplicate from the same document, with no need to tell which.
+func (om *OrderedMap[K, V]) seenKeys() map[K]struct{} {
+	if om.Len() == 0 && (om.duplicateKeyPolicy == LastWinsKeepPosition || om.duplicateKeyPolicy == LastWinsMoveToBack) {
+		return nil
+	}
+	return make(map[K]struct{})
+}
+
+// setDecoded sets a pair decoded from a document, according to the map's DuplicateKeyPolicy.
+// seen holds the keys decoded so far from the same document, see seenKeys; it returns false iff key
+// is a duplicate that must be rejected.
+func (om *OrderedMap[K, V]) setDecoded(seen map[K]struct{}, key K, value V) bool {
+	pair, present := om.pairs[key]
+
+	duplicate := present
+	if seen != nil {
+		_, duplicate = seen[key]
+		seen[key] = struct{}{}
+	}
+
+	switch {
+	case !present:
+		om.insert(key, value)
+	case !duplicate:
+		// keys already present prior to decoding are set the same way Set would
+		om.write(pair, value)
+	case om.duplicateKeyPolicy == RejectDuplicates:
+		return false
+	case om.duplicateKeyPolicy == FirstWins:
+	case om.duplicateKeyPolicy == LastWinsMoveToBack:
+		pair.Value = value
+		om.moveToBack(pair)
+	default:
+		pair.Value = value
+	}
+
+	return true
+}
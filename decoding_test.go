@@ -0,0 +1,134 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDuplicateKeyPolicies(t *testing.T) {
+	jsonInput := `{"a":1,"b":2,"a":3,"c":4}`
+	yamlInput := "a: 1\nb: 2\na: 3\nc: 4\n"
+
+	decoders := map[string]func(om *OrderedMap[string, int]) error{
+		"UnmarshalJSON": func(om *OrderedMap[string, int]) error {
+			return json.Unmarshal([]byte(jsonInput), om)
+		},
+		"DecodeJSON": func(om *OrderedMap[string, int]) error {
+			return om.DecodeJSON(strings.NewReader(jsonInput))
+		},
+		"UnmarshalYAML": func(om *OrderedMap[string, int]) error {
+			return yaml.Unmarshal([]byte(yamlInput), om)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			for _, testCase := range []struct {
+				policy         DuplicateKeyPolicy
+				expectedKeys   []string
+				expectedValues []int
+			}{
+				{LastWinsKeepPosition, []string{"a", "b", "c"}, []int{3, 2, 4}},
+				{LastWinsMoveToBack, []string{"b", "a", "c"}, []int{2, 3, 4}},
+				{FirstWins, []string{"a", "b", "c"}, []int{1, 2, 4}},
+			} {
+				om := New[string, int](WithDuplicateKeyPolicy[string, int](testCase.policy))
+				require.NoError(t, decode(om))
+				assertOrderedPairsEqual(t, om, testCase.expectedKeys, testCase.expectedValues)
+			}
+
+			// the default policy
+			om := New[string, int]()
+			require.NoError(t, decode(om))
+			assertOrderedPairsEqual(t, om, []string{"a", "b", "c"}, []int{3, 2, 4})
+
+			// keys already present in the map aren't duplicates
+			om = New[string, int](WithDuplicateKeyPolicy[string, int](FirstWins))
+			om.Set("c", 28)
+			om.Set("d", 5)
+			require.NoError(t, decode(om))
+			assertOrderedPairsEqual(t, om, []string{"c", "d", "a", "b"}, []int{4, 5, 1, 2})
+
+			om = New[string, int](WithDuplicateKeyPolicy[string, int](RejectDuplicates))
+			var duplicateErr *DuplicateKeyError[string]
+			require.ErrorAs(t, decode(om), &duplicateErr)
+			assert.Equal(t, "a", duplicateErr.Key)
+		})
+	}
+
+	t.Run("error locations", func(t *testing.T) {
+		newMap := func() *OrderedMap[string, int] {
+			return New[string, int](WithDuplicateKeyPolicy[string, int](RejectDuplicates))
+		}
+
+		assert.EqualError(t, json.Unmarshal([]byte(jsonInput), newMap()), "duplicate key a at offset 13")
+		assert.EqualError(t, newMap().DecodeJSON(strings.NewReader(jsonInput)), "duplicate key a at offset 13")
+		assert.EqualError(t, yaml.Unmarshal([]byte(yamlInput), newMap()), "duplicate key a at line 3, column 1")
+
+		// with whitespace, string values and escaped quotes
+		input := "{ \"a\\\"b\" : \"x\" ,\n  \"c\": \"y\\\"\",\n\t\"a\\\"b\"\t:  \"z\" }"
+		expected := fmt.Sprintf(`duplicate key a"b at offset %d`, strings.LastIndex(input, `"a\"b"`))
+		newStringMap := func() *OrderedMap[string, string] {
+			return New[string, string](WithDuplicateKeyPolicy[string, string](RejectDuplicates))
+		}
+		assert.EqualError(t, json.Unmarshal([]byte(input), newStringMap()), expected)
+		assert.EqualError(t, newStringMap().DecodeJSON(strings.NewReader(input)), expected)
+	})
+
+	t.Run("ordering policies", func(t *testing.T) {
+		// keys already present in the map are moved as Set would move them, while duplicates
+		// within the document follow the duplicate key policy
+		for _, policy := range []OrderingPolicy{WriteOrder, AccessOrder} {
+			for name, decode := range decoders {
+				om := New[string, int](WithOrderingPolicy[string, int](policy))
+				om.Set("a", 10)
+				om.Set("b", 20)
+				om.Set("c", 30)
+				om.Set("d", 40)
+
+				require.NoError(t, decode(om), name)
+				assertOrderedPairsEqual(t, om, []string{"d", "a", "b", "c"}, []int{40, 3, 2, 4})
+			}
+		}
+	})
+
+	t.Run("nested maps", func(t *testing.T) {
+		input := `{"x":{"a":1,"b":2,"a":3}}`
+
+		om := New[string, any](
+			WithNestedJSONDecoding[string, any](),
+			WithDuplicateKeyPolicy[string, any](LastWinsMoveToBack),
+		)
+		require.NoError(t, json.Unmarshal([]byte(input), om))
+		nested, ok := om.Value("x").(*OrderedMap[string, any])
+		require.True(t, ok)
+		assertOrderedPairsEqual(t, nested, []string{"b", "a"}, []any{json.Number("2"), json.Number("3")})
+		assert.Equal(t, LastWinsMoveToBack, nested.duplicateKeyPolicy)
+
+		// offsets are relative to the whole input, not to the nested object
+		deeperInput := `{"w": 1, "y": [ 2, {"z": {"a": 1, "a": 2}} ]}`
+		for _, input := range []string{input, deeperInput} {
+			expectedOffset := int64(strings.LastIndex(input, `"a"`))
+
+			for _, decode := range []func(om *OrderedMap[string, any]) error{
+				func(om *OrderedMap[string, any]) error { return json.Unmarshal([]byte(input), om) },
+				func(om *OrderedMap[string, any]) error { return om.DecodeJSON(strings.NewReader(input)) },
+			} {
+				om = New[string, any](
+					WithNestedJSONDecoding[string, any](),
+					WithDuplicateKeyPolicy[string, any](RejectDuplicates),
+				)
+				var duplicateErr *DuplicateKeyError[string]
+				require.ErrorAs(t, decode(om), &duplicateErr)
+				assert.Equal(t, "a", duplicateErr.Key)
+				assert.Equal(t, expectedOffset, duplicateErr.Offset, input)
+			}
+		}
+	})
+}
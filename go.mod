@@ -3,7 +3,6 @@ module github.com/wk8/go-ordered-map/v2
 go 1.23
 
 require (
-	github.com/bahlo/generic-list-go v0.2.0
 	github.com/buger/jsonparser v1.1.1
 	github.com/mailru/easyjson v0.7.7
 	github.com/stretchr/testify v1.8.1
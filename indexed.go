@@ -0,0 +1,286 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// WithIndexing makes the map maintain an index of its pairs' positions, so that positional
+// operations (At, IndexOf, InsertAt, DeleteAt and MoveTo) run in logarithmic time instead
+// of linear time.
+// The price to pay is that all the operations that change the map's structure (inserting,
+// deleting or moving pairs) then also run in logarithmic time instead of constant time,
+// and that the map uses more memory.
+func WithIndexing[K comparable, V any]() InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.indexed = true
+	}
+}
+
+// IndexOutOfRangeError may be returned by functions in this package when they're called with
+// positions that are out of the map's bounds.
+type IndexOutOfRangeError struct {
+	Index int
+	Len   int
+}
+
+func (e *IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("index %d out of range [0:%d]", e.Index, e.Len)
+}
+
+// At returns the pair at the given position, the oldest pair being at index 0;
+// or nil if index is out of range.
+// Runs in logarithmic time if the map was created WithIndexing, linear time otherwise.
+func (om *OrderedMap[K, V]) At(index int) *Pair[K, V] {
+	if index < 0 || index >= om.Len() {
+		return nil
+	}
+	return om.at(index)
+}
+
+// IndexOf returns the position of the given key in the map, the oldest pair being at index 0;
+// or -1 if the key is not present.
+// Runs in logarithmic time if the map was created WithIndexing, linear time otherwise.
+func (om *OrderedMap[K, V]) IndexOf(key K) int {
+	pair, present := om.pairs[key]
+	if !present {
+		return -1
+	}
+	return om.indexOf(pair)
+}
+
+// InsertAt sets the key-value pair, and moves it so that it ends up at the given position.
+// index must be between 0 and Len() - 1 if the key is already present, or Len() if it isn't,
+// otherwise an IndexOutOfRangeError is returned and the map is left untouched.
+// Runs in logarithmic time if the map was created WithIndexing, linear time otherwise.
+func (om *OrderedMap[K, V]) InsertAt(index int, key K, value V) error {
+	pair, present := om.pairs[key]
+
+	maxIndex := om.Len()
+	if present {
+		maxIndex--
+	}
+	if index < 0 || index > maxIndex {
+		return &IndexOutOfRangeError{Index: index, Len: maxIndex + 1}
+	}
+
+	if present {
+		pair.Value = value
+	} else {
+		pair = om.insert(key, value)
+	}
+	om.moveTo(pair, index)
+
+	return nil
+}
+
+// DeleteAt removes the pair at the given position, and returns it; or returns nil if index
+// is out of range.
+// Runs in logarithmic time if the map was created WithIndexing, linear time otherwise.
+func (om *OrderedMap[K, V]) DeleteAt(index int) *Pair[K, V] {
+	pair := om.At(index)
+	if pair != nil {
+		om.remove(pair)
+	}
+	return pair
+}
+
+// MoveTo moves the value associated with key so that it ends up at the given position.
+// Returns a KeyNotFoundError if key is not present in the map, or an IndexOutOfRangeError
+// if index is not between 0 and Len() - 1.
+// Runs in logarithmic time if the map was created WithIndexing, linear time otherwise.
+func (om *OrderedMap[K, V]) MoveTo(key K, index int) error {
+	pair, present := om.pairs[key]
+	if !present {
+		return &KeyNotFoundError[K]{key}
+	}
+	if index < 0 || index >= om.Len() {
+		return &IndexOutOfRangeError{Index: index, Len: om.Len()}
+	}
+
+	om.moveTo(pair, index)
+	return nil
+}
+
+func (om *OrderedMap[K, V]) moveTo(pair *Pair[K, V], index int) {
+	current := om.indexOf(pair)
+	switch {
+	case index < current:
+		om.moveBefore(pair, om.at(index))
+	case index > current:
+		om.moveAfter(pair, om.at(index))
+	}
+}
+
+// at assumes that index is within bounds.
+func (om *OrderedMap[K, V]) at(index int) *Pair[K, V] {
+	if om.index != nil {
+		return om.index.at(index)
+	}
+
+	if index < om.Len()/2 {
+		pair := om.Oldest()
+		for ; index > 0; index-- {
+			pair = pair.Next()
+		}
+		return pair
+	}
+
+	pair := om.Newest()
+	for index = om.Len() - 1 - index; index > 0; index-- {
+		pair = pair.Prev()
+	}
+	return pair
+}
+
+func (om *OrderedMap[K, V]) indexOf(pair *Pair[K, V]) (index int) {
+	if om.index != nil {
+		return om.index.rank(pair)
+	}
+
+	for pair = pair.Prev(); pair != nil; pair = pair.Prev() {
+		index++
+	}
+	return
+}
+
+// orderIndex is an implicit treap, i.e. a randomized balanced binary tree whose in-order
+// traversal yields the map's pairs in order. Nodes store the size of their subtree, and
+// link to their parents, which allows finding a pair's position in logarithmic time.
+type orderIndex[K comparable, V any] struct {
+	root *indexNode[K, V]
+}
+
+type indexNode[K comparable, V any] struct {
+	pair     *Pair[K, V]
+	priority uint32
+	size     int
+
+	left, right, parent *indexNode[K, V]
+}
+
+func nodeSize[K comparable, V any](node *indexNode[K, V]) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// update must be called on a node after its children have changed.
+func (n *indexNode[K, V]) update() {
+	n.size = 1 + nodeSize(n.left) + nodeSize(n.right)
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+}
+
+// split splits the tree rooted at node into one tree containing its first k nodes,
+// and another one containing the rest.
+func split[K comparable, V any](node *indexNode[K, V], k int) (left, right *indexNode[K, V]) {
+	if node == nil {
+		return nil, nil
+	}
+
+	if nodeSize(node.left) >= k {
+		left, node.left = split(node.left, k)
+		right = node
+	} else {
+		node.right, right = split(node.right, k-nodeSize(node.left)-1)
+		left = node
+	}
+	node.update()
+
+	return
+}
+
+// merge concatenates two trees.
+func merge[K comparable, V any](left, right *indexNode[K, V]) *indexNode[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left.right = merge(left.right, right)
+		left.update()
+		return left
+	default:
+		right.left = merge(left, right.left)
+		right.update()
+		return right
+	}
+}
+
+func (idx *orderIndex[K, V]) setRoot(root *indexNode[K, V]) {
+	if root != nil {
+		root.parent = nil
+	}
+	idx.root = root
+}
+
+func (idx *orderIndex[K, V]) pushBack(pair *Pair[K, V]) {
+	idx.insertAt(pair, nodeSize(idx.root))
+}
+
+// insertAt inserts the pair at position k; the pair must not be in the index already.
+func (idx *orderIndex[K, V]) insertAt(pair *Pair[K, V], k int) {
+	node := pair.node
+	if node == nil {
+		node = &indexNode[K, V]{
+			pair:     pair,
+			priority: rand.Uint32(), //nolint:gosec
+		}
+		pair.node = node
+	}
+	node.left, node.right, node.parent = nil, nil, nil
+	node.update()
+
+	left, right := split(idx.root, k)
+	idx.setRoot(merge(merge(left, node), right))
+}
+
+// detach removes the pair from the index, but keeps its node around for re-insertion.
+func (idx *orderIndex[K, V]) detach(pair *Pair[K, V]) {
+	left, right := split(idx.root, idx.rank(pair))
+	if right != nil {
+		right.parent = nil
+	}
+	_, right = split(right, 1)
+	idx.setRoot(merge(left, right))
+}
+
+func (idx *orderIndex[K, V]) remove(pair *Pair[K, V]) {
+	idx.detach(pair)
+	pair.node = nil
+}
+
+func (idx *orderIndex[K, V]) rank(pair *Pair[K, V]) int {
+	node := pair.node
+	rank := nodeSize(node.left)
+	for ; node.parent != nil; node = node.parent {
+		if node == node.parent.right {
+			rank += nodeSize(node.parent.left) + 1
+		}
+	}
+	return rank
+}
+
+// at assumes that k is within bounds.
+func (idx *orderIndex[K, V]) at(k int) *Pair[K, V] {
+	node := idx.root
+	for {
+		leftSize := nodeSize(node.left)
+		switch {
+		case k < leftSize:
+			node = node.left
+		case k == leftSize:
+			return node.pair
+		default:
+			k -= leftSize + 1
+			node = node.right
+		}
+	}
+}
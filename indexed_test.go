@@ -0,0 +1,166 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionalAccess(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			om := newMaybeIndexed[string, int](indexed)
+			om.Set("a", 1)
+			om.Set("b", 2)
+			om.Set("c", 3)
+			om.Set("d", 4)
+
+			assert.Equal(t, "a", om.At(0).Key)
+			assert.Equal(t, "c", om.At(2).Key)
+			assert.Equal(t, "d", om.At(3).Key)
+			assert.Nil(t, om.At(4))
+			assert.Nil(t, om.At(-1))
+
+			assert.Equal(t, 0, om.IndexOf("a"))
+			assert.Equal(t, 3, om.IndexOf("d"))
+			assert.Equal(t, -1, om.IndexOf("i dont exist"))
+
+			require.NoError(t, om.InsertAt(1, "e", 5))
+			assertOrderedPairsEqual(t, om,
+				[]string{"a", "e", "b", "c", "d"},
+				[]int{1, 5, 2, 3, 4})
+
+			require.NoError(t, om.InsertAt(5, "f", 6))
+			require.NoError(t, om.InsertAt(0, "g", 7))
+			assertOrderedPairsEqual(t, om,
+				[]string{"g", "a", "e", "b", "c", "d", "f"},
+				[]int{7, 1, 5, 2, 3, 4, 6})
+
+			// inserting an existing key updates and moves it
+			require.NoError(t, om.InsertAt(6, "a", 10))
+			assertOrderedPairsEqual(t, om,
+				[]string{"g", "e", "b", "c", "d", "f", "a"},
+				[]int{7, 5, 2, 3, 4, 6, 10})
+
+			assert.Equal(t, &IndexOutOfRangeError{Index: 7, Len: 7}, om.InsertAt(7, "a", 10))
+			assert.Equal(t, &IndexOutOfRangeError{Index: 8, Len: 8}, om.InsertAt(8, "h", 8))
+			assert.Equal(t, &IndexOutOfRangeError{Index: -1, Len: 8}, om.InsertAt(-1, "h", 8))
+
+			require.NoError(t, om.MoveTo("g", 3))
+			require.NoError(t, om.MoveTo("f", 1))
+			require.NoError(t, om.MoveTo("c", 3))
+			assertOrderedPairsEqual(t, om,
+				[]string{"e", "f", "b", "c", "g", "d", "a"},
+				[]int{5, 6, 2, 3, 7, 4, 10})
+
+			assert.Equal(t, &KeyNotFoundError[string]{"h"}, om.MoveTo("h", 0))
+			assert.Equal(t, &IndexOutOfRangeError{Index: 7, Len: 7}, om.MoveTo("a", 7))
+
+			pair := om.DeleteAt(1)
+			if assert.NotNil(t, pair) {
+				assert.Equal(t, "f", pair.Key)
+				assert.Equal(t, 6, pair.Value)
+			}
+			assert.Nil(t, om.DeleteAt(6))
+			assertOrderedPairsEqual(t, om,
+				[]string{"e", "b", "c", "g", "d", "a"},
+				[]int{5, 2, 3, 7, 4, 10})
+		})
+	}
+}
+
+func TestIndexedMapConsistency(t *testing.T) {
+	indexed := New[int, int](WithIndexing[int, int]())
+	plain := New[int, int]()
+
+	rng := rand.New(rand.NewPCG(28, 12)) //nolint:gosec
+	for i := 0; i < 5000; i++ {
+		key := rng.IntN(200)
+		switch op := rng.IntN(9); op {
+		case 0, 1:
+			indexed.Set(key, i)
+			plain.Set(key, i)
+		case 2:
+			indexed.Delete(key)
+			plain.Delete(key)
+		case 3:
+			markKey := rng.IntN(200)
+			assert.Equal(t, plain.MoveAfter(key, markKey), indexed.MoveAfter(key, markKey))
+		case 4:
+			markKey := rng.IntN(200)
+			assert.Equal(t, plain.MoveBefore(key, markKey), indexed.MoveBefore(key, markKey))
+		case 5:
+			assert.Equal(t, plain.MoveToFront(key), indexed.MoveToFront(key))
+		case 6:
+			assert.Equal(t, plain.MoveToBack(key), indexed.MoveToBack(key))
+		case 7:
+			index := rng.IntN(plain.Len() + 1)
+			assert.Equal(t, plain.InsertAt(index, key, i), indexed.InsertAt(index, key, i))
+		case 8:
+			index := rng.IntN(plain.Len() + 1)
+			assert.Equal(t, plain.MoveTo(key, index), indexed.MoveTo(key, index))
+		}
+
+		assert.Equal(t, plain.IndexOf(key), indexed.IndexOf(key))
+	}
+
+	assertIndexConsistent(t, indexed)
+
+	var keys []int
+	var values []int
+	for k, v := range plain.FromOldest() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	assertOrderedPairsEqual(t, indexed, keys, values)
+
+	for i := 0; i < indexed.Len(); i++ {
+		assert.Equal(t, plain.At(i).Key, indexed.At(i).Key)
+	}
+}
+
+func newMaybeIndexed[K comparable, V any](indexed bool) *OrderedMap[K, V] {
+	if indexed {
+		return New[K, V](WithIndexing[K, V]())
+	}
+	return New[K, V]()
+}
+
+// assertIndexConsistent checks that the map's index matches its list, and that its
+// internal invariants hold.
+func assertIndexConsistent[K comparable, V any](t *testing.T, om *OrderedMap[K, V]) {
+	t.Helper()
+
+	require.NotNil(t, om.index)
+	if om.index.root != nil {
+		assert.Nil(t, om.index.root.parent)
+	}
+	assert.Equal(t, om.Len(), nodeSize(om.index.root))
+
+	var checkNode func(node *indexNode[K, V])
+	checkNode = func(node *indexNode[K, V]) {
+		if node == nil {
+			return
+		}
+		assert.Equal(t, 1+nodeSize(node.left)+nodeSize(node.right), node.size)
+		assert.Same(t, node, node.pair.node)
+		for _, child := range []*indexNode[K, V]{node.left, node.right} {
+			if child != nil {
+				assert.Same(t, node, child.parent)
+				assert.LessOrEqual(t, child.priority, node.priority)
+				checkNode(child)
+			}
+		}
+	}
+	checkNode(om.index.root)
+
+	i := 0
+	for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		assert.Same(t, pair, om.index.at(i))
+		assert.Equal(t, i, om.index.rank(pair))
+		i++
+	}
+}
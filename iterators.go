@@ -0,0 +1,123 @@
+package orderedmap
+
+import "iter"
+
+// PairsFromOldest returns an iterator over all the pairs in the map, starting from the oldest pair.
+// Unlike the other iterators, it supports deleting the current pair while iterating; and the pairs'
+// values can be updated in place.
+func (om *OrderedMap[K, V]) PairsFromOldest() iter.Seq[*Pair[K, V]] {
+	return om.walk(om.Oldest, (*Pair[K, V]).Next)
+}
+
+// PairsFromNewest is the same as PairsFromOldest, starting from the newest pair.
+func (om *OrderedMap[K, V]) PairsFromNewest() iter.Seq[*Pair[K, V]] {
+	return om.walk(om.Newest, (*Pair[K, V]).Prev)
+}
+
+// FromKey returns an iterator over the key-value pairs in the map, starting from the pair associated
+// with key, up to the newest pair. It yields nothing if key is not present in the map.
+func (om *OrderedMap[K, V]) FromKey(key K) iter.Seq2[K, V] {
+	return keyValues(om.walk(om.pairGetter(key), (*Pair[K, V]).Next))
+}
+
+// FromKeyBackward returns an iterator over the key-value pairs in the map, starting from the pair
+// associated with key, down to the oldest pair. It yields nothing if key is not present in the map.
+func (om *OrderedMap[K, V]) FromKeyBackward(key K) iter.Seq2[K, V] {
+	return keyValues(om.walk(om.pairGetter(key), (*Pair[K, V]).Prev))
+}
+
+// Between returns an iterator over the key-value pairs in the map from the pair associated with
+// startKey to the one associated with endKey, both included. It yields nothing if either key
+// is not present in the map, or if endKey comes before startKey.
+func (om *OrderedMap[K, V]) Between(startKey, endKey K) iter.Seq2[K, V] {
+	return om.between(startKey, endKey, true)
+}
+
+// BetweenExclusive is the same as Between, but excludes the pairs associated with startKey and endKey.
+func (om *OrderedMap[K, V]) BetweenExclusive(startKey, endKey K) iter.Seq2[K, V] {
+	return om.between(startKey, endKey, false)
+}
+
+func (om *OrderedMap[K, V]) between(startKey, endKey K, inclusive bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		start, end := om.GetPair(startKey), om.GetPair(endKey)
+		if start == nil || end == nil || !om.precedes(start, end) {
+			return
+		}
+
+		for pair := range om.walk(func() *Pair[K, V] { return start }, (*Pair[K, V]).Next) {
+			last := pair == end
+			if (inclusive || (pair != start && !last)) && !yield(pair.Key, pair.Value) {
+				return
+			}
+			if last {
+				return
+			}
+		}
+	}
+}
+
+// precedes returns true iff pair comes before or is the same as other.
+func (om *OrderedMap[K, V]) precedes(pair, other *Pair[K, V]) bool {
+	if om.index != nil {
+		return om.index.rank(pair) <= om.index.rank(other)
+	}
+	for ; pair != nil; pair = pair.Next() {
+		if pair == other {
+			return true
+		}
+	}
+	return false
+}
+
+// walk iterates from the pair returned by start, using step to get to the next one. It supports
+// the current pair being deleted by the caller, in which case it carries on from where the
+// current pair was, provided that the pair that came next is still in the map.
+// If the map was created WithCheckedIterators, any other structural modification panics.
+func (om *OrderedMap[K, V]) walk(start func() *Pair[K, V], step func(*Pair[K, V]) *Pair[K, V]) iter.Seq[*Pair[K, V]] {
+	return func(yield func(*Pair[K, V]) bool) {
+		for pair := start(); pair != nil; {
+			modCount, next := om.modCount, step(pair)
+			if !yield(pair) {
+				return
+			}
+
+			deleted := !om.contains(pair)
+			if deleted {
+				modCount++
+			}
+			if om.checked && om.modCount != modCount {
+				panic(concurrentModificationMessage)
+			}
+
+			switch {
+			case !deleted:
+				pair = step(pair)
+			case next != nil && om.contains(next):
+				pair = next
+			default:
+				return
+			}
+		}
+	}
+}
+
+func (om *OrderedMap[K, V]) pairGetter(key K) func() *Pair[K, V] {
+	return func() *Pair[K, V] {
+		return om.GetPair(key)
+	}
+}
+
+func (om *OrderedMap[K, V]) contains(pair *Pair[K, V]) bool {
+	return om.pairs[pair.Key] == pair
+}
+
+func keyValues[K comparable, V any](pairs iter.Seq[*Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for pair := range pairs {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package orderedmap
+
+import (
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairsIterators(t *testing.T) {
+	for _, mode := range storageModes[int, int]() {
+		t.Run(mode.name, func(t *testing.T) {
+			om := New[int, int](mode.options...)
+			for i := 0; i < 10; i++ {
+				om.Set(i, i)
+			}
+
+			// update values, and delete the odd ones, while iterating
+			for pair := range om.PairsFromOldest() {
+				pair.Value *= 10
+				if pair.Key%2 == 1 {
+					om.Delete(pair.Key)
+				}
+			}
+			assertOrderedPairsEqual(t, om, []int{0, 2, 4, 6, 8}, []int{0, 20, 40, 60, 80})
+
+			var keys []int
+			for pair := range om.PairsFromNewest() {
+				keys = append(keys, pair.Key)
+				om.Delete(pair.Key)
+			}
+			assert.Equal(t, []int{8, 6, 4, 2, 0}, keys)
+			assertLenEqual(t, om, 0)
+		})
+	}
+}
+
+func TestPairsIteratorsStopWhenNextPairIsDeleted(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 5; i++ {
+		om.Set(i, i)
+	}
+
+	var keys []int
+	for pair := range om.PairsFromOldest() {
+		keys = append(keys, pair.Key)
+		if pair.Key == 1 {
+			om.Delete(1)
+			om.Delete(2)
+		}
+	}
+	assert.Equal(t, []int{0, 1}, keys)
+}
+
+func TestFromKey(t *testing.T) {
+	for _, mode := range storageModes[string, int]() {
+		t.Run(mode.name, func(t *testing.T) {
+			om := New[string, int](mode.options...)
+			for i, key := range []string{"a", "b", "c", "d", "e"} {
+				om.Set(key, i)
+			}
+
+			assertSeq2Equal(t, om.FromKey("c"), []string{"c", "d", "e"}, []int{2, 3, 4})
+			assertSeq2Equal(t, om.FromKey("e"), []string{"e"}, []int{4})
+			assertSeq2Equal(t, om.FromKeyBackward("c"), []string{"c", "b", "a"}, []int{2, 1, 0})
+			assertSeq2Equal(t, om.FromKeyBackward("a"), []string{"a"}, []int{0})
+			assertSeq2Equal[string, int](t, om.FromKey("i dont exist"), nil, nil)
+			assertSeq2Equal[string, int](t, om.FromKeyBackward("i dont exist"), nil, nil)
+
+			// breaking early
+			for k := range om.FromKey("b") {
+				assert.Equal(t, "b", k)
+				break
+			}
+
+			// the starting key is looked up when the iteration starts
+			it := om.FromKey("b")
+			om.Delete("b")
+			om.Set("b", 28)
+			assertSeq2Equal(t, it, []string{"b"}, []int{28})
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	for _, mode := range storageModes[string, int]() {
+		t.Run(mode.name, func(t *testing.T) {
+			om := New[string, int](mode.options...)
+			for i, key := range []string{"a", "b", "c", "d", "e"} {
+				om.Set(key, i)
+			}
+
+			assertSeq2Equal(t, om.Between("b", "d"), []string{"b", "c", "d"}, []int{1, 2, 3})
+			assertSeq2Equal(t, om.BetweenExclusive("b", "d"), []string{"c"}, []int{2})
+			assertSeq2Equal(t, om.Between("a", "e"), []string{"a", "b", "c", "d", "e"}, []int{0, 1, 2, 3, 4})
+			assertSeq2Equal(t, om.Between("c", "c"), []string{"c"}, []int{2})
+			assertSeq2Equal[string, int](t, om.BetweenExclusive("c", "c"), nil, nil)
+			assertSeq2Equal[string, int](t, om.BetweenExclusive("c", "d"), nil, nil)
+
+			// end before start
+			assertSeq2Equal[string, int](t, om.Between("d", "b"), nil, nil)
+			assertSeq2Equal[string, int](t, om.BetweenExclusive("d", "b"), nil, nil)
+
+			// missing keys
+			assertSeq2Equal[string, int](t, om.Between("i dont exist", "d"), nil, nil)
+			assertSeq2Equal[string, int](t, om.Between("b", "i dont exist"), nil, nil)
+
+			// breaking early
+			for k := range om.Between("b", "d") {
+				assert.Equal(t, "b", k)
+				break
+			}
+		})
+	}
+}
+
+type storageMode struct {
+	name    string
+	options []any
+}
+
+// storageModes lists the options for all the ways a map can keep track of its order.
+func storageModes[K comparable, V any]() []storageMode {
+	return []storageMode{
+		{name: "default"},
+		{name: "indexed", options: []any{WithIndexing[K, V]()}},
+		{name: "compact", options: []any{WithCompactStorage[K, V](0.5)}},
+	}
+}
+
+func assertSeq2Equal[K comparable, V any](t *testing.T, seq iter.Seq2[K, V], expectedKeys []K, expectedValues []V) {
+	t.Helper()
+
+	var keys []K
+	var values []V
+	for k, v := range seq {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	assert.Equal(t, expectedKeys, keys, fmt.Sprintf("expected values %v, got %v", expectedValues, values))
+	assert.Equal(t, expectedValues, values)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/buger/jsonparser"
@@ -17,15 +18,98 @@ var (
 	_ json.Unmarshaler = &OrderedMap[int, any]{}
 )
 
+// WithNestedJSONDecoding makes UnmarshalJSON decode values the same way as UnmarshalNestedJSON,
+// i.e. decode all nested JSON objects into *OrderedMap[string, any]s so that their keys' order is
+// preserved, arrays into []any, and numbers into json.Numbers.
+// It only applies to maps whose values are of type any; nested maps inherit the map's options
+// affecting JSON.
+func WithNestedJSONDecoding[K comparable, V any]() InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.nestedJSONDecoding = true
+	}
+}
+
+// UnmarshalNestedJSON decodes an arbitrary JSON document, like json.Unmarshal would into an any,
+// except that objects are decoded into *OrderedMap[string, any]s instead of map[string]any, and
+// numbers into json.Numbers instead of float64s. That way, marshalling the result back to JSON
+// yields the same document, with all keys in the same order.
+func UnmarshalNestedJSON(data []byte) (any, error) {
+	// jsonparser ignores whatever follows the first value, so the whole input must be validated
+	// first; json.Unmarshal then reports the same errors as it would for any other target
+	if !json.Valid(data) {
+		return nil, json.Unmarshal(data, new(struct{}))
+	}
+	return unmarshalNestedJSON(data, jsonDecodingOptions{}, 0)
+}
+
+// unmarshalNestedJSON is UnmarshalNestedJSON, for a document found at the given offset in the input.
+func unmarshalNestedJSON(data []byte, options jsonDecodingOptions, inputOffset int64) (any, error) {
+	value, dataType, offset, err := jsonparser.Get(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNestedJSON(value, dataType, options, inputOffset+int64(offset-len(value)))
+}
+
+// jsonDecodingOptions are the options inherited by nested maps, see WithNestedJSONDecoding.
+type jsonDecodingOptions struct {
+	disableHTMLEscape  bool
+	duplicateKeyPolicy DuplicateKeyPolicy
+}
+
+func (om *OrderedMap[K, V]) jsonDecodingOptions() jsonDecodingOptions {
+	return jsonDecodingOptions{
+		disableHTMLEscape:  om.disableHTMLEscape,
+		duplicateKeyPolicy: om.duplicateKeyPolicy,
+	}
+}
+
 // MarshalJSON implements the json.Marshaler interface.
-func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) { //nolint:funlen
-	if om == nil || om.list == nil {
-		return []byte("null"), nil
+// The output is compact, unless the map was created WithJSONIndent.
+func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var writer jwriter.Writer
+	if err := om.writeJSON(&writer, om.configuredJSONIndentation(), 0, nil); err != nil {
+		return nil, err
 	}
+	return dumpWriter(&writer)
+}
 
-	writer := jwriter.Writer{
-		NoEscapeHTML: om.disableHTMLEscape,
+// MarshalJSONIndent is like MarshalJSON, but applies indentation to format the output, the same
+// way as json.MarshalIndent: each JSON element begins on a new line beginning with prefix followed
+// by one or more copies of indent according to the indentation nesting.
+// Nested OrderedMaps are indented natively; other values are indented by json.MarshalIndent.
+func (om *OrderedMap[K, V]) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	var writer jwriter.Writer
+	if err := om.writeJSON(&writer, &jsonIndentation{prefix: prefix, indent: indent}, 0, nil); err != nil {
+		return nil, err
 	}
+	return dumpWriter(&writer)
+}
+
+// jsonWriter is implemented by OrderedMaps of all types, to write nested maps natively.
+type jsonWriter interface {
+	writeJSON(writer *jwriter.Writer, indentation *jsonIndentation, depth int, flush func() error) error
+}
+
+var _ jsonWriter = &OrderedMap[int, any]{}
+
+// writeJSON writes the map as JSON at the given nesting depth, and calls flush, if not nil,
+// after each pair. indentation is nil for compact output.
+func (om *OrderedMap[K, V]) writeJSON(
+	writer *jwriter.Writer,
+	indentation *jsonIndentation,
+	depth int,
+	flush func() error,
+) error {
+	if om == nil || om.list == nil {
+		writer.RawString("null")
+		return nil
+	}
+
+	noEscapeHTML := writer.NoEscapeHTML
+	writer.NoEscapeHTML = om.disableHTMLEscape
+	defer func() { writer.NoEscapeHTML = noEscapeHTML }()
+
 	writer.RawByte('{')
 
 	for pair, firstIteration := om.Oldest(), true; pair != nil; pair = pair.Next() {
@@ -34,58 +118,135 @@ func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) { //nolint:funlen
 		} else {
 			writer.RawByte(',')
 		}
+		indentation.newLine(writer, depth+1)
 
-		switch key := any(pair.Key).(type) {
-		case string:
-			writer.String(key)
-		case encoding.TextMarshaler:
-			writer.RawByte('"')
-			writer.Raw(key.MarshalText())
-			writer.RawByte('"')
-		case int:
-			writer.IntStr(key)
-		case int8:
-			writer.Int8Str(key)
-		case int16:
-			writer.Int16Str(key)
-		case int32:
-			writer.Int32Str(key)
-		case int64:
-			writer.Int64Str(key)
-		case uint:
-			writer.UintStr(key)
-		case uint8:
-			writer.Uint8Str(key)
-		case uint16:
-			writer.Uint16Str(key)
-		case uint32:
-			writer.Uint32Str(key)
-		case uint64:
-			writer.Uint64Str(key)
-		default:
+		if err := writeJSONKey(writer, pair.Key); err != nil {
+			return err
+		}
+		writer.RawByte(':')
 
-			// this switch takes care of wrapper types around primitive types, such as
-			// type myType string
-			switch keyValue := reflect.ValueOf(key); keyValue.Type().Kind() {
-			case reflect.String:
-				writer.String(keyValue.String())
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				writer.Int64Str(keyValue.Int())
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				writer.Uint64Str(keyValue.Uint())
-			default:
-				return nil, fmt.Errorf("unsupported key type: %T", key)
+		if indentation == nil {
+			// the error is checked when dumping the writer
+			writer.Raw(jsonMarshal(pair.Value, om.disableHTMLEscape))
+		} else {
+			writer.RawByte(' ')
+			if err := om.writeIndentedJSONValue(writer, pair.Value, indentation, depth+1); err != nil {
+				return err
 			}
 		}
 
-		writer.RawByte(':')
-		// the error is checked at the end of the function
-		writer.Raw(jsonMarshal(pair.Value, om.disableHTMLEscape))
+		if flush != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
 	}
 
+	if om.Len() != 0 {
+		indentation.newLine(writer, depth)
+	}
 	writer.RawByte('}')
 
-	return dumpWriter(&writer)
+	return nil
+}
+
+func (om *OrderedMap[K, V]) writeIndentedJSONValue(
+	writer *jwriter.Writer,
+	value V,
+	indentation *jsonIndentation,
+	depth int,
+) error {
+	if nested, ok := any(value).(jsonWriter); ok {
+		return nested.writeJSON(writer, indentation, depth, nil)
+	}
+
+	prefix := indentation.prefix + strings.Repeat(indentation.indent, depth)
+	// the error is checked when dumping the writer
+	writer.Raw(jsonMarshalIndent(value, prefix, indentation.indent, om.disableHTMLEscape))
+	return nil
+}
+
+// configuredJSONIndentation returns the indentation set WithJSONIndent, if any.
+func (om *OrderedMap[K, V]) configuredJSONIndentation() *jsonIndentation {
+	if om == nil {
+		return nil
+	}
+	return om.jsonIndentation
+}
+
+// jsonIndentation describes how to indent JSON output.
+type jsonIndentation struct {
+	prefix, indent string
+}
+
+// WithJSONIndent makes MarshalJSON and EncodeJSON indent their output, see MarshalJSONIndent.
+// Note that encoding/json compacts the output of MarshalJSON methods, so that this has no effect
+// on json.Marshal; json.MarshalIndent can be used instead.
+func WithJSONIndent[K comparable, V any](prefix, indent string) InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.jsonIndentation = &jsonIndentation{prefix: prefix, indent: indent}
+	}
+}
+
+// newLine starts a new line at the given nesting depth; it's a no-op for compact output.
+func (i *jsonIndentation) newLine(writer *jwriter.Writer, depth int) {
+	if i == nil {
+		return
+	}
+
+	writer.RawByte('\n')
+	writer.RawString(i.prefix)
+	for range depth {
+		writer.RawString(i.indent)
+	}
+}
+
+// writeJSONKey writes key as a JSON object key, i.e. as a string.
+func writeJSONKey(writer *jwriter.Writer, key any) error {
+	switch key := key.(type) {
+	case string:
+		writer.String(key)
+	case encoding.TextMarshaler:
+		writer.RawByte('"')
+		writer.Raw(key.MarshalText())
+		writer.RawByte('"')
+	case int:
+		writer.IntStr(key)
+	case int8:
+		writer.Int8Str(key)
+	case int16:
+		writer.Int16Str(key)
+	case int32:
+		writer.Int32Str(key)
+	case int64:
+		writer.Int64Str(key)
+	case uint:
+		writer.UintStr(key)
+	case uint8:
+		writer.Uint8Str(key)
+	case uint16:
+		writer.Uint16Str(key)
+	case uint32:
+		writer.Uint32Str(key)
+	case uint64:
+		writer.Uint64Str(key)
+	default:
+
+		// this switch takes care of wrapper types around primitive types, such as
+		// type myType string
+		switch keyValue := reflect.ValueOf(key); keyValue.Type().Kind() {
+		case reflect.String:
+			writer.String(keyValue.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			writer.Int64Str(keyValue.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			writer.Uint64Str(keyValue.Uint())
+		default:
+			return fmt.Errorf("unsupported key type: %T", key)
+		}
+	}
+
+	return nil
 }
 
 func jsonMarshal(t interface{}, disableHTMLEscape bool) ([]byte, error) {
@@ -100,6 +261,19 @@ func jsonMarshal(t interface{}, disableHTMLEscape bool) ([]byte, error) {
 	return json.Marshal(t)
 }
 
+func jsonMarshalIndent(t interface{}, prefix, indent string, disableHTMLEscape bool) ([]byte, error) {
+	if disableHTMLEscape {
+		buffer := &bytes.Buffer{}
+		encoder := json.NewEncoder(buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent(prefix, indent)
+		err := encoder.Encode(t)
+		// Encode() adds an extra newline, strip it off to guarantee same behavior as json.MarshalIndent
+		return bytes.TrimRight(buffer.Bytes(), "\n"), err
+	}
+	return json.MarshalIndent(t, prefix, indent)
+}
+
 func dumpWriter(writer *jwriter.Writer) ([]byte, error) {
 	if writer.Error != nil {
 		return nil, writer.Error
@@ -116,67 +290,173 @@ func dumpWriter(writer *jwriter.Writer) ([]byte, error) {
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	return om.unmarshalJSON(data, 0)
+}
+
+// unmarshalJSON is UnmarshalJSON, for an object found at the given offset in the input: that
+// offset is only used to report the location of duplicate keys.
+func (om *OrderedMap[K, V]) unmarshalJSON(data []byte, inputOffset int64) error {
 	if om.list == nil {
 		om.initialize(0, om.disableHTMLEscape)
 	}
 
+	seen := om.seenKeys()
 	return jsonparser.ObjectEach(
 		data,
 		func(keyData []byte, valueData []byte, dataType jsonparser.ValueType, offset int) error {
-			if dataType == jsonparser.String {
-				// jsonparser removes the enclosing quotes; we need to restore them to make a valid JSON
-				valueData = data[offset-len(valueData)-2 : offset]
+			key, err := decodeJSONKey[K](keyData)
+			if err != nil {
+				return err
 			}
 
-			var key K
-			var value V
+			raw := rawJSONValue(data, valueData, dataType, offset)
+			valueOffset := offset - len(raw)
 
-			switch typedKey := any(&key).(type) {
-			case *string:
-				s, err := decodeUTF8(keyData)
+			var value V
+			if target, isAny := any(&value).(*any); isAny && om.nestedJSONDecoding {
+				*target, err = decodeNestedJSON(valueData, dataType, om.jsonDecodingOptions(), inputOffset+int64(valueOffset))
 				if err != nil {
 					return err
 				}
-				*typedKey = s
-			case encoding.TextUnmarshaler:
-				if err := typedKey.UnmarshalText(keyData); err != nil {
-					return err
-				}
-			case *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16, *uint32, *uint64:
-				if err := json.Unmarshal(keyData, typedKey); err != nil {
-					return err
-				}
-			default:
-				// this switch takes care of wrapper types around primitive types, such as
-				// type myType string
-				switch reflect.TypeOf(key).Kind() {
-				case reflect.String:
-					s, err := decodeUTF8(keyData)
-					if err != nil {
-						return err
-					}
-
-					convertedKeyData := reflect.ValueOf(s).Convert(reflect.TypeOf(key))
-					reflect.ValueOf(&key).Elem().Set(convertedKeyData)
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					if err := json.Unmarshal(keyData, &key); err != nil {
-						return err
-					}
-				default:
-					return fmt.Errorf("unsupported key type: %T", key)
-				}
-			}
-
-			if err := json.Unmarshal(valueData, &value); err != nil {
+			} else if err := json.Unmarshal(raw, &value); err != nil {
 				return err
 			}
 
-			om.Set(key, value)
+			if !om.setDecoded(seen, key, value) {
+				return &DuplicateKeyError[K]{Key: key, Offset: inputOffset + int64(jsonKeyOffset(data, valueOffset))}
+			}
 			return nil
 		})
 }
 
+// decodeNestedJSON decodes a value found by jsonparser at the given offset in the input,
+// see UnmarshalNestedJSON.
+func decodeNestedJSON(
+	data []byte, dataType jsonparser.ValueType, options jsonDecodingOptions, inputOffset int64,
+) (any, error) {
+	switch dataType {
+	case jsonparser.Object:
+		om := New[string, any](
+			WithNestedJSONDecoding[string, any](),
+			WithDuplicateKeyPolicy[string, any](options.duplicateKeyPolicy),
+		)
+		om.disableHTMLEscape = options.disableHTMLEscape
+		if err := om.unmarshalJSON(data, inputOffset); err != nil {
+			return nil, err
+		}
+		return om, nil
+
+	case jsonparser.Array:
+		array := make([]any, 0)
+		var elementErr error
+		_, err := jsonparser.ArrayEach(data, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+			if err == nil && elementErr == nil {
+				var element any
+				element, elementErr = decodeNestedJSON(value, dataType, options, inputOffset+int64(offset))
+				array = append(array, element)
+			}
+		})
+		if err == nil {
+			err = elementErr
+		}
+		return array, err
+
+	case jsonparser.String:
+		return jsonparser.ParseString(data)
+
+	case jsonparser.Number:
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("invalid JSON number: %s", data)
+		}
+		return json.Number(data), nil
+
+	case jsonparser.Boolean:
+		return jsonparser.ParseBoolean(data)
+
+	case jsonparser.Null:
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected JSON value: %s", data)
+	}
+}
+
+// rawJSONValue returns the raw JSON for a value found by jsonparser in data.
+func rawJSONValue(data, valueData []byte, dataType jsonparser.ValueType, offset int) []byte {
+	if dataType == jsonparser.String {
+		// jsonparser removes the enclosing quotes; we need to restore them to make a valid JSON
+		return data[offset-len(valueData)-2 : offset]
+	}
+	return valueData
+}
+
+// jsonKeyOffset returns the offset in data of the opening quote of the object key preceding the
+// value starting at valueOffset.
+func jsonKeyOffset(data []byte, valueOffset int) int {
+	// skip the colon and whitespace, to reach the key's closing quote
+	offset := valueOffset - 1
+	for offset > 0 && data[offset] != '"' {
+		offset--
+	}
+
+	// then look for the opening quote, i.e. the first one not escaped by an odd number of backslashes
+	for offset--; offset > 0; offset-- {
+		if data[offset] != '"' {
+			continue
+		}
+		backslashes := 0
+		for data[offset-backslashes-1] == '\\' {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			break
+		}
+	}
+	return offset
+}
+
+// decodeJSONKey decodes a JSON object key, as found between the enclosing quotes.
+func decodeJSONKey[K comparable](keyData []byte) (key K, err error) {
+	switch typedKey := any(&key).(type) {
+	case *string:
+		s, err := decodeUTF8(keyData)
+		if err != nil {
+			return key, err
+		}
+		*typedKey = s
+	case encoding.TextUnmarshaler:
+		if err := typedKey.UnmarshalText(keyData); err != nil {
+			return key, err
+		}
+	case *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16, *uint32, *uint64:
+		if err := json.Unmarshal(keyData, typedKey); err != nil {
+			return key, err
+		}
+	default:
+		// this switch takes care of wrapper types around primitive types, such as
+		// type myType string
+		switch reflect.TypeOf(key).Kind() {
+		case reflect.String:
+			s, err := decodeUTF8(keyData)
+			if err != nil {
+				return key, err
+			}
+
+			convertedKeyData := reflect.ValueOf(s).Convert(reflect.TypeOf(key))
+			reflect.ValueOf(&key).Elem().Set(convertedKeyData)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if err := json.Unmarshal(keyData, &key); err != nil {
+				return key, err
+			}
+		default:
+			return key, fmt.Errorf("unsupported key type: %T", key)
+		}
+	}
+
+	return key, nil
+}
+
 func decodeUTF8(input []byte) (string, error) {
 	remaining, offset := input, 0
 	runes := make([]rune, 0, len(remaining))
@@ -0,0 +1,113 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSONIndent(t *testing.T) {
+	nested := New[int, any]()
+	nested.Set(2, []any{1, "<b>", map[string]int{"z": 1, "a": 2}})
+	nested.Set(1, New[string, int]())
+	nested.Set(3, (*OrderedMap[string, int])(nil))
+
+	om := New[string, any]()
+	om.Set("z", nested)
+	om.Set("a", struct {
+		Foo string `json:"foo"`
+	}{"bar"})
+	om.Set("m", "<tag>")
+	om.Set("e", []int{})
+
+	for _, indentation := range []jsonIndentation{
+		{"", "  "},
+		{"", "\t"},
+		{"// ", "    "},
+		{"", ""},
+	} {
+		t.Run(indentation.prefix+"|"+indentation.indent, func(t *testing.T) {
+			expected, err := json.MarshalIndent(om, indentation.prefix, indentation.indent)
+			require.NoError(t, err)
+
+			actual, err := om.MarshalJSONIndent(indentation.prefix, indentation.indent)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+
+	t.Run("output", func(t *testing.T) {
+		actual, err := om.MarshalJSONIndent("", "  ")
+		require.NoError(t, err)
+		assert.Equal(t, `{
+  "z": {
+    "2": [
+      1,
+      "\u003cb\u003e",
+      {
+        "a": 2,
+        "z": 1
+      }
+    ],
+    "1": {},
+    "3": null
+  },
+  "a": {
+    "foo": "bar"
+  },
+  "m": "\u003ctag\u003e",
+  "e": []
+}`, string(actual))
+	})
+
+	t.Run("disabling HTML escape", func(t *testing.T) {
+		inner := New[string, string](WithDisableHTMLEscape[string, string]())
+		inner.Set("<k>", "<v>")
+		outer := New[string, any](WithDisableHTMLEscape[string, any]())
+		outer.Set("<a>", inner)
+		outer.Set("<b>", []string{"<c>"})
+
+		actual, err := outer.MarshalJSONIndent("", " ")
+		require.NoError(t, err)
+		assert.Equal(t, "{\n \"<a>\": {\n  \"<k>\": \"<v>\"\n },\n \"<b>\": [\n  \"<c>\"\n ]\n}", string(actual))
+	})
+
+	t.Run("nil and errors", func(t *testing.T) {
+		var nilMap *OrderedMap[string, int]
+		actual, err := nilMap.MarshalJSONIndent("", "  ")
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(actual))
+
+		unsupported := New[string, any]()
+		unsupported.Set("a", func() {})
+		_, err = unsupported.MarshalJSONIndent("", "  ")
+		assert.Error(t, err)
+	})
+}
+
+func TestWithJSONIndent(t *testing.T) {
+	om := New[string, any](WithJSONIndent[string, any]("", "  "))
+	om.Set("b", 1)
+	om.Set("a", []int{2})
+
+	expected := "{\n  \"b\": 1,\n  \"a\": [\n    2\n  ]\n}"
+
+	actual, err := om.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(actual))
+
+	var buffer bytes.Buffer
+	require.NoError(t, om.EncodeJSON(&buffer))
+	assert.Equal(t, expected, buffer.String())
+
+	// encoding/json compacts the output of MarshalJSON
+	actual, err = json.Marshal(om)
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":1,"a":[2]}`, string(actual))
+
+	assert.Equal(t, om.jsonIndentation, om.Clone().jsonIndentation)
+}
@@ -0,0 +1,186 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"unicode/utf8"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// EncodeJSON writes the same JSON as MarshalJSON to w, but streams it one pair at a time,
+// instead of building the whole document in memory first.
+func (om *OrderedMap[K, V]) EncodeJSON(w io.Writer) error {
+	var writer jwriter.Writer
+	flush := func() error { return flushWriter(&writer, w) }
+
+	if err := om.writeJSON(&writer, om.configuredJSONIndentation(), 0, flush); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func flushWriter(writer *jwriter.Writer, w io.Writer) error {
+	if writer.Error != nil {
+		return writer.Error
+	}
+	_, err := writer.DumpTo(w)
+	return err
+}
+
+// DecodeJSON reads a JSON object from r, and sets its pairs in the map, the same way as UnmarshalJSON;
+// but it streams the object one pair at a time, instead of requiring the whole document in memory.
+// It reads exactly one JSON value from r, possibly buffering some of the data after it.
+func (om *OrderedMap[K, V]) DecodeJSON(r io.Reader) error {
+	if om.list == nil {
+		om.initialize(0, om.disableHTMLEscape)
+	}
+
+	decoder := NewJSONDecoder[K, V](r)
+	if om.nestedJSONDecoding {
+		options := om.jsonDecodingOptions()
+		decoder.nested = &options
+	}
+
+	seen := om.seenKeys()
+	for key, value := range decoder.Pairs() {
+		if !om.setDecoded(seen, key, value) {
+			return &DuplicateKeyError[K]{Key: key, Offset: decoder.keyOffset}
+		}
+	}
+	return decoder.Err()
+}
+
+// JSONDecoder decodes a JSON object from a reader one pair at a time, allowing to process
+// huge objects without holding them in memory. Its zero value is not usable; instances must
+// be created with NewJSONDecoder.
+type JSONDecoder[K comparable, V any] struct {
+	decoder *json.Decoder
+	input   *inputRecorder
+	// only set to decode nested maps, see WithNestedJSONDecoding
+	nested *jsonDecodingOptions
+	// the offset of the last decoded pair's key
+	keyOffset int64
+	err       error
+}
+
+// NewJSONDecoder creates a new JSONDecoder reading from r.
+func NewJSONDecoder[K comparable, V any](r io.Reader) *JSONDecoder[K, V] {
+	input := &inputRecorder{reader: r}
+	return &JSONDecoder[K, V]{decoder: json.NewDecoder(input), input: input}
+}
+
+// Pairs returns an iterator over the key-value pairs of the JSON object read from the reader,
+// decoded as UnmarshalJSON would. It stops at the end of the object, on the first error, or
+// when the caller stops iterating, in which case the rest of the object is left unread.
+// Err must be called after iterating to check for errors.
+// The iterator is meant to be used only once.
+func (d *JSONDecoder[K, V]) Pairs() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if !d.expectDelim('{') {
+			return
+		}
+
+		for d.decoder.More() {
+			key, value, ok := d.decodePair()
+			if !ok || !yield(key, value) {
+				return
+			}
+		}
+
+		d.expectDelim('}')
+	}
+}
+
+// Err returns the first error encountered while decoding, if any.
+func (d *JSONDecoder[K, V]) Err() error {
+	return d.err
+}
+
+func (d *JSONDecoder[K, V]) decodePair() (key K, value V, ok bool) {
+	d.input.discard(d.decoder.InputOffset())
+	token, err := d.decoder.Token()
+	if err != nil {
+		d.err = err
+		return
+	}
+	keyString, isString := token.(string)
+	if !isString {
+		d.err = fmt.Errorf("expected a JSON object key at offset %d, got %v", d.decoder.InputOffset(), token)
+		return
+	}
+	if !d.checkRawKey() {
+		return
+	}
+	if key, err = decodeJSONKey[K]([]byte(keyString)); err != nil {
+		d.err = err
+		return
+	}
+
+	if target, isAny := any(&value).(*any); isAny && d.nested != nil {
+		var raw json.RawMessage
+		if err = d.decoder.Decode(&raw); err == nil {
+			*target, err = unmarshalNestedJSON(raw, *d.nested, d.decoder.InputOffset()-int64(len(raw)))
+		}
+	} else {
+		err = d.decoder.Decode(&value)
+	}
+	if err != nil {
+		d.err = err
+		return
+	}
+
+	return key, value, true
+}
+
+// checkRawKey locates the key that was just read in the raw input, and checks that it's valid
+// UTF-8: json.Decoder silently replaces invalid bytes, which UnmarshalJSON rejects.
+func (d *JSONDecoder[K, V]) checkRawKey() bool {
+	keyEnd := int(d.decoder.InputOffset() - d.input.offset)
+	keyStart := jsonKeyOffset(d.input.data, keyEnd)
+	d.keyOffset = d.input.offset + int64(keyStart)
+
+	if rawKey := d.input.data[keyStart+1 : keyEnd-1]; !utf8.Valid(rawKey) {
+		_, d.err = decodeUTF8(rawKey)
+		return false
+	}
+	return true
+}
+
+func (d *JSONDecoder[K, V]) expectDelim(delim json.Delim) bool {
+	if d.err != nil {
+		return false
+	}
+
+	token, err := d.decoder.Token()
+	switch {
+	case err != nil:
+		d.err = err
+	case token != delim:
+		d.err = fmt.Errorf("expected %v at offset %d, got %v", delim, d.decoder.InputOffset(), token)
+	}
+	return d.err == nil
+}
+
+// inputRecorder keeps the data read from a reader, from a given offset on.
+type inputRecorder struct {
+	reader io.Reader
+	data   []byte
+	// the offset in the input of data's first byte
+	offset int64
+}
+
+func (r *inputRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.data = append(r.data, p[:n]...)
+	return n, err
+}
+
+// discard forgets the data before offset.
+func (r *inputRecorder) discard(offset int64) {
+	n := copy(r.data, r.data[offset-r.offset:])
+	r.data = r.data[:n]
+	r.offset = offset
+}
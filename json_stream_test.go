@@ -0,0 +1,180 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	om := New[string, any]()
+	om.Set("b", []int{1, 2})
+	om.Set("<a>", map[string]string{"c": "<d>"})
+	om.Set("e", nil)
+
+	for _, disableHTMLEscape := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disableHTMLEscape=%v", disableHTMLEscape), func(t *testing.T) {
+			om.disableHTMLEscape = disableHTMLEscape
+
+			var buffer bytes.Buffer
+			require.NoError(t, om.EncodeJSON(&buffer))
+
+			expected, err := om.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), buffer.String())
+		})
+	}
+
+	t.Run("empty and nil", func(t *testing.T) {
+		var buffer bytes.Buffer
+		require.NoError(t, New[int, int]().EncodeJSON(&buffer))
+		assert.Equal(t, `{}`, buffer.String())
+
+		buffer.Reset()
+		var nilMap *OrderedMap[int, int]
+		require.NoError(t, nilMap.EncodeJSON(&buffer))
+		assert.Equal(t, `null`, buffer.String())
+	})
+
+	t.Run("streams pairs", func(t *testing.T) {
+		writer := &countingWriter{}
+		require.NoError(t, om.EncodeJSON(writer))
+		assert.Equal(t, om.Len()+1, writer.writes)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		assert.Equal(t, writeErr, om.EncodeJSON(&countingWriter{err: writeErr}))
+
+		unsupported := New[struct{}, int]()
+		unsupported.Set(struct{}{}, 1)
+		assert.Error(t, unsupported.EncodeJSON(&bytes.Buffer{}))
+	})
+}
+
+type countingWriter struct {
+	writes int
+	err    error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), w.err
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("same as UnmarshalJSON", func(t *testing.T) {
+		input := `{"#2#":[1,2],"#3#":{"c":"d"},"#5#":null,"#2#":"f"}`
+
+		expected := New[marshallable, any]()
+		require.NoError(t, json.Unmarshal([]byte(input), expected))
+
+		om := New[marshallable, any]()
+		require.NoError(t, om.DecodeJSON(strings.NewReader(input)))
+		assert.True(t, EqualFunc(expected, om, func(v1, v2 any) bool {
+			return fmt.Sprint(v1) == fmt.Sprint(v2)
+		}))
+	})
+
+	t.Run("with nested JSON decoding", func(t *testing.T) {
+		input := `{"z":{"y":[1.50,{"b":null,"a":true}],"x":"w"},"a":-1e+28}`
+
+		om := New[string, any](WithNestedJSONDecoding[string, any]())
+		require.NoError(t, om.DecodeJSON(strings.NewReader(input)))
+
+		out, err := json.Marshal(om)
+		require.NoError(t, err)
+		assert.Equal(t, input, string(out))
+	})
+
+	t.Run("into a zero value", func(t *testing.T) {
+		var om OrderedMap[int, string]
+		require.NoError(t, om.DecodeJSON(strings.NewReader(`{"2":"a","1":"b"}`)))
+		assertOrderedPairsEqual(t, &om, []int{2, 1}, []string{"a", "b"})
+	})
+
+	t.Run("invalid UTF-8 keys", func(t *testing.T) {
+		input := "{\"a\":1,\"b\xff\":2}"
+
+		unmarshalErr := json.Unmarshal([]byte(input), New[string, int]())
+		require.Error(t, unmarshalErr)
+
+		om := New[string, int]()
+		assert.EqualError(t, om.DecodeJSON(strings.NewReader(input)), unmarshalErr.Error())
+		// even when reading one byte at a time
+		om = New[string, int]()
+		assert.EqualError(t, om.DecodeJSON(iotest.OneByteReader(strings.NewReader(input))), unmarshalErr.Error())
+	})
+
+	t.Run("one byte at a time", func(t *testing.T) {
+		input := `{ "a" : 1 , "b\"c" : 2, "a": 3 }`
+
+		om := New[string, int]()
+		require.NoError(t, om.DecodeJSON(iotest.OneByteReader(strings.NewReader(input))))
+		assertOrderedPairsEqual(t, om, []string{"a", `b"c`}, []int{3, 2})
+
+		om = New[string, int](WithDuplicateKeyPolicy[string, int](RejectDuplicates))
+		err := om.DecodeJSON(iotest.OneByteReader(strings.NewReader(input)))
+		assert.EqualError(t, err, fmt.Sprintf("duplicate key a at offset %d", strings.LastIndex(input, `"a"`)))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, input := range []string{
+			``,
+			`null`,
+			`[1]`,
+			`{"a":1`,
+			`{"a":1,}`,
+			`{"a":"b"}`,
+			`{"1":"b"}`,
+		} {
+			om := New[string, int]()
+			assert.Error(t, om.DecodeJSON(strings.NewReader(input)), input)
+		}
+	})
+}
+
+func TestJSONDecoder(t *testing.T) {
+	reader := strings.NewReader(`{"a":1,"b":2,"c":3} {"d":4}`)
+
+	decoder := NewJSONDecoder[string, int](reader)
+	var keys []string
+	var values []int
+	for key, value := range decoder.Pairs() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	require.NoError(t, decoder.Err())
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	// stopping early
+	decoder = NewJSONDecoder[string, int](strings.NewReader(`{"a":1,"b":2,"c":3}`))
+	for key := range decoder.Pairs() {
+		assert.Equal(t, "a", key)
+		break
+	}
+	require.NoError(t, decoder.Err())
+
+	// errors stop the iteration
+	decoder = NewJSONDecoder[string, int](strings.NewReader(`{"a":1,"b":"c","d":4}`))
+	keys = nil
+	for key := range decoder.Pairs() {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"a"}, keys)
+	assert.Error(t, decoder.Err())
+
+	decoder = NewJSONDecoder[string, int](strings.NewReader(`["a"]`))
+	for range decoder.Pairs() {
+		t.Fatal("should not yield")
+	}
+	assert.EqualError(t, decoder.Err(), "expected { at offset 1, got [")
+}
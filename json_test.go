@@ -315,6 +315,13 @@ func TestJSONRoundTrip(t *testing.T) {
 			input:         `{"�":0}`,
 			targetFactory: func() any { return &OrderedMap[string, int]{} },
 		},
+		{
+			name:  "with nested JSON decoding",
+			input: `{"z":{"y":[1.50,{"b":null,"a":true}],"x":"w"},"c":[],"b":{},"a":-1e+28}`,
+			targetFactory: func() any {
+				return New[string, any](WithNestedJSONDecoding[string, any]())
+			},
+		},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			target := testCase.targetFactory()
@@ -338,6 +345,66 @@ func TestJSONRoundTrip(t *testing.T) {
 	}
 }
 
+func TestNestedJSONDecoding(t *testing.T) {
+	t.Run("UnmarshalNestedJSON", func(t *testing.T) {
+		decoded, err := UnmarshalNestedJSON([]byte(` {"b":[{"d":1,"c":"\u00e9"}],"a":12345678901234567890} `))
+		require.NoError(t, err)
+
+		om, ok := decoded.(*OrderedMap[string, any])
+		require.True(t, ok)
+		assertOrderedPairsEqual(t, om, []string{"b", "a"}, []any{om.Value("b"), json.Number("12345678901234567890")})
+
+		array, ok := om.Value("b").([]any)
+		require.True(t, ok)
+		require.Len(t, array, 1)
+		nested, ok := array[0].(*OrderedMap[string, any])
+		require.True(t, ok)
+		assertOrderedPairsEqual(t, nested, []string{"d", "c"}, []any{json.Number("1"), "é"})
+
+		for input, expected := range map[string]any{
+			`"foo"`: "foo",
+			`28`:    json.Number("28"),
+			`true`:  true,
+			`null`:  nil,
+			`[]`:    []any{},
+		} {
+			decoded, err := UnmarshalNestedJSON([]byte(input))
+			require.NoError(t, err)
+			assert.Equal(t, expected, decoded)
+		}
+
+		for _, input := range []string{
+			``, `{"a":}`, `[1,{"a":-}]`, `{"a":[1,2}`, `{"a":01}`,
+			`{"a":1} garbage`, `{"a":1}}`, `1 2`, `{"a":1,}`,
+		} {
+			_, err := UnmarshalNestedJSON([]byte(input))
+			require.Error(t, err, input)
+			// same error as json.Unmarshal's
+			assert.EqualError(t, err, json.Unmarshal([]byte(input), new(any)).Error(), input)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		om := New[string, any](WithNestedJSONDecoding[string, any](), WithDisableHTMLEscape[string, any]())
+		require.NoError(t, json.Unmarshal([]byte(`{"b":{"<d>":1,"c":2},"a":null}`), om))
+
+		nested, ok := om.Value("b").(*OrderedMap[string, any])
+		require.True(t, ok)
+		assertOrderedPairsEqual(t, nested, []string{"<d>", "c"}, []any{json.Number("1"), json.Number("2")})
+		assert.True(t, nested.disableHTMLEscape)
+		assert.True(t, nested.nestedJSONDecoding)
+
+		value, present := om.Get("a")
+		assert.True(t, present)
+		assert.Nil(t, value)
+
+		// only applies to maps with values of type any
+		typed := New[string, map[string]int](WithNestedJSONDecoding[string, map[string]int]())
+		require.NoError(t, json.Unmarshal([]byte(`{"a":{"c":1,"b":2}}`), typed))
+		assert.Equal(t, map[string]int{"b": 2, "c": 1}, typed.Value("a"))
+	})
+}
+
 func BenchmarkMarshalJSON(b *testing.B) {
 	om := New[int, any]()
 	om.Set(1, "bar")
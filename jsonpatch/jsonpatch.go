@@ -0,0 +1,546 @@
+// Package jsonpatch implements JSON Patch (RFC 6902) on ordered JSON documents, as decoded by
+// orderedmap.UnmarshalNestedJSON: objects are *orderedmap.OrderedMap[string, any], and arrays []any.
+// Unlike implementations operating on regular maps, it preserves the order of objects' members:
+// "add" appends new members at the end of their object, and Diff expresses order changes as "move"
+// operations.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Object is the type of JSON objects in the documents this package operates on.
+type Object = orderedmap.OrderedMap[string, any]
+
+// OpType is the type of a JSON Patch operation.
+type OpType string
+
+const (
+	Add     OpType = "add"
+	Remove  OpType = "remove"
+	Replace OpType = "replace"
+	Move    OpType = "move"
+	Copy    OpType = "copy"
+	Test    OpType = "test"
+)
+
+var (
+	ErrInvalidPointer   = errors.New("invalid JSON pointer")
+	ErrPathNotFound     = errors.New("path not found")
+	ErrInvalidIndex     = errors.New("invalid array index")
+	ErrInvalidMove      = errors.New("cannot move a value into one of its own children")
+	ErrRootRemoval      = errors.New("cannot remove the whole document")
+	ErrTestFailed       = errors.New("test failed")
+	ErrUnknownOperation = errors.New("unknown operation")
+)
+
+// Operation is a single JSON Patch operation. From is only used by "move" and "copy", and Value
+// by "add", "replace" and "test".
+type Operation struct {
+	Op    OpType
+	Path  string
+	From  string
+	Value any
+}
+
+// Patch is a JSON Patch document, i.e. a list of operations to apply in order.
+type Patch []Operation
+
+// OperationError is returned by Apply when one of the patch's operations fails.
+type OperationError struct {
+	// Index is the position of the failed operation in the patch.
+	Index     int
+	Operation Operation
+	Err       error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s %s): %v", e.Index, e.Operation.Op, e.Operation.Path, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Apply applies patch to a copy of doc, and returns that copy. doc itself is never modified, so
+// that it is left untouched when an operation fails, as RFC 6902 requires.
+func Apply(doc any, patch Patch) (any, error) {
+	doc = deepCopy(doc)
+	for i, operation := range patch {
+		var err error
+		if doc, err = operation.apply(doc); err != nil {
+			return nil, &OperationError{Index: i, Operation: operation, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+// ApplyJSON is like Apply, but takes and returns JSON-encoded documents and patches.
+func ApplyJSON(doc, patch []byte) ([]byte, error) {
+	decodedDoc, err := orderedmap.UnmarshalNestedJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var decodedPatch Patch
+	if err := json.Unmarshal(patch, &decodedPatch); err != nil {
+		return nil, err
+	}
+
+	patched, err := Apply(decodedDoc, decodedPatch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(patched)
+}
+
+func (o Operation) apply(doc any) (any, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case Add:
+		return add(doc, path, deepCopy(o.Value))
+	case Remove:
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case Replace:
+		return replace(doc, path, deepCopy(o.Value))
+	case Move:
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+			return nil, ErrInvalidMove
+		}
+
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case Copy:
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(value))
+	case Test:
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, o.Value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, ErrUnknownOperation
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	fields := orderedmap.New[string, any]()
+	fields.Set("op", o.Op)
+	if o.Op == Move || o.Op == Copy {
+		fields.Set("from", o.From)
+	}
+	fields.Set("path", o.Path)
+	if o.Op == Add || o.Op == Replace || o.Op == Test {
+		fields.Set("value", o.Value)
+	}
+	return fields.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Values are decoded the same way as by
+// orderedmap.UnmarshalNestedJSON.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	fields := orderedmap.New[string, any](orderedmap.WithNestedJSONDecoding[string, any]())
+	if err := json.Unmarshal(data, fields); err != nil {
+		return err
+	}
+
+	stringField := func(name string) (string, error) {
+		value, present := fields.Get(name)
+		if !present {
+			return "", fmt.Errorf("missing %q field in JSON patch operation", name)
+		}
+		str, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("%q field of JSON patch operation must be a string, got %v", name, value)
+		}
+		return str, nil
+	}
+
+	op, err := stringField("op")
+	if err != nil {
+		return err
+	}
+	operation := Operation{Op: OpType(op)}
+	if operation.Path, err = stringField("path"); err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case Move, Copy:
+		if operation.From, err = stringField("from"); err != nil {
+			return err
+		}
+	case Add, Replace, Test:
+		var present bool
+		if operation.Value, present = fields.Get("value"); !present {
+			return fmt.Errorf("missing \"value\" field in JSON patch %s operation", operation.Op)
+		}
+	case Remove:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOperation, op)
+	}
+
+	*o = operation
+	return nil
+}
+
+// Diff returns a patch turning from into to, including the order of their objects' members:
+// applying it to from yields a document equal to to, with members in the same order.
+// Members already in the right relative order are left in place, and the others are moved to the
+// end of their object.
+func Diff(from, to any) Patch {
+	return diff(nil, "", from, to)
+}
+
+func diff(patch Patch, path string, from, to any) Patch {
+	switch to := to.(type) {
+	case *Object:
+		if from, ok := from.(*Object); ok {
+			return diffObjects(patch, path, from, to)
+		}
+	case []any:
+		if from, ok := from.([]any); ok {
+			return diffArrays(patch, path, from, to)
+		}
+	}
+
+	if !equal(from, to) {
+		patch = append(patch, Operation{Op: Replace, Path: path, Value: deepCopy(to)})
+	}
+	return patch
+}
+
+func diffObjects(patch Patch, path string, from, to *Object) Patch {
+	// the keys present in both objects, in from's order
+	kept := make([]string, 0, from.Len())
+	for key := range from.KeysFromOldest() {
+		if to.GetPair(key) == nil {
+			patch = append(patch, Operation{Op: Remove, Path: memberPath(path, key)})
+		} else {
+			kept = append(kept, key)
+		}
+	}
+
+	for pair := to.Oldest(); pair != nil; pair = pair.Next() {
+		if fromPair := from.GetPair(pair.Key); fromPair != nil {
+			patch = diff(patch, memberPath(path, pair.Key), fromPair.Value, pair.Value)
+		}
+	}
+
+	// the longest prefix of to's keys that appear in the same relative order in from can stay
+	// in place
+	pair, i := to.Oldest(), 0
+	for ; pair != nil; pair, i = pair.Next(), i+1 {
+		for i < len(kept) && kept[i] != pair.Key {
+			i++
+		}
+		if i == len(kept) {
+			break
+		}
+	}
+
+	// then all subsequent members get appended, in order; since adding a member to an object
+	// appends it, moving a member to its own path moves it to the end of the object
+	for ; pair != nil; pair = pair.Next() {
+		memberPath := memberPath(path, pair.Key)
+		if from.GetPair(pair.Key) == nil {
+			patch = append(patch, Operation{Op: Add, Path: memberPath, Value: deepCopy(pair.Value)})
+		} else {
+			patch = append(patch, Operation{Op: Move, From: memberPath, Path: memberPath})
+		}
+	}
+
+	return patch
+}
+
+func diffArrays(patch Patch, path string, from, to []any) Patch {
+	common := min(len(from), len(to))
+	for i := range common {
+		patch = diff(patch, path+"/"+strconv.Itoa(i), from[i], to[i])
+	}
+	for i := len(from) - 1; i >= common; i-- {
+		patch = append(patch, Operation{Op: Remove, Path: path + "/" + strconv.Itoa(i)})
+	}
+	for _, value := range to[common:] {
+		patch = append(patch, Operation{Op: Add, Path: path + "/-", Value: deepCopy(value)})
+	}
+	return patch
+}
+
+func memberPath(path, key string) string {
+	return path + "/" + strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j == len(token)-1 || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, pointer)
+			}
+			j++
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array index, which must be lower than limit.
+func arrayIndex(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.Trim(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidIndex, token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index >= limit {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidIndex, token)
+	}
+	return index, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case *Object:
+			pair := node.GetPair(token)
+			if pair == nil {
+				return nil, ErrPathNotFound
+			}
+			doc = pair.Value
+		case []any:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return doc, nil
+}
+
+// update walks doc down to the parent of the location at path, replaces that parent with the
+// result of calling updateParent on it and the last token of path, and returns the updated doc.
+// path must not be empty.
+func update(doc any, path []string, updateParent func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return updateParent(doc, path[0])
+	}
+
+	switch node := doc.(type) {
+	case *Object:
+		pair := node.GetPair(path[0])
+		if pair == nil {
+			return nil, ErrPathNotFound
+		}
+		child, err := update(pair.Value, path[1:], updateParent)
+		if err != nil {
+			return nil, err
+		}
+		pair.Value = child
+		return node, nil
+	case []any:
+		index, err := arrayIndex(path[0], len(node))
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(node[index], path[1:], updateParent)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case *Object:
+			// existing members are replaced in place, regardless of the object's ordering policy
+			if pair := node.GetPair(token); pair != nil {
+				pair.Value = value
+			} else {
+				node.Set(token, value)
+			}
+			return node, nil
+		case []any:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			index, err := arrayIndex(token, len(node)+1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(node, index, value), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+func remove(doc any, path []string) (updated, removed any, err error) {
+	if len(path) == 0 {
+		return nil, nil, ErrRootRemoval
+	}
+
+	updated, err = update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case *Object:
+			var present bool
+			if removed, present = node.Delete(token); !present {
+				return nil, ErrPathNotFound
+			}
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return slices.Delete(node, index, index+1), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+	return updated, removed, err
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case *Object:
+			pair := node.GetPair(token)
+			if pair == nil {
+				return nil, ErrPathNotFound
+			}
+			pair.Value = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node[index] = value
+			return node, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+func deepCopy(value any) any {
+	switch value := value.(type) {
+	case *Object:
+		return value.CloneFunc(deepCopy)
+	case []any:
+		copied := make([]any, len(value))
+		for i, element := range value {
+			copied[i] = deepCopy(element)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// equal compares two JSON values as RFC 6902's "test" operation does: objects are equal if they
+// have the same members regardless of their order, and numbers if they are numerically equal.
+func equal(value1, value2 any) bool {
+	switch value1 := value1.(type) {
+	case *Object:
+		value2, ok := value2.(*Object)
+		if !ok || value1.Len() != value2.Len() {
+			return false
+		}
+		for pair1 := value1.Oldest(); pair1 != nil; pair1 = pair1.Next() {
+			if pair2 := value2.GetPair(pair1.Key); pair2 == nil || !equal(pair1.Value, pair2.Value) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		value2, ok := value2.([]any)
+		return ok && slices.EqualFunc(value1, value2, equal)
+	}
+
+	if number1, ok := number(value1); ok {
+		number2, ok := number(value2)
+		return ok && number1.Cmp(number2) == 0
+	}
+	return reflect.DeepEqual(value1, value2)
+}
+
+func number(value any) (*big.Rat, bool) {
+	if value, ok := value.(json.Number); ok {
+		return new(big.Rat).SetString(string(value))
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch {
+	case reflected.CanInt():
+		return new(big.Rat).SetInt64(reflected.Int()), true
+	case reflected.CanUint():
+		return new(big.Rat).SetUint64(reflected.Uint()), true
+	case reflected.CanFloat():
+		rat := new(big.Rat).SetFloat64(reflected.Float())
+		return rat, rat != nil
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,336 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestApplyJSON(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "adding an object member appends it",
+			doc:      `{"foo":"bar","baz":"qux"}`,
+			patch:    `[{"op":"add","path":"/abc","value":"xyz"}]`,
+			expected: `{"foo":"bar","baz":"qux","abc":"xyz"}`,
+		},
+		{
+			name:     "adding an existing member replaces it in place",
+			doc:      `{"foo":"bar","baz":"qux"}`,
+			patch:    `[{"op":"add","path":"/foo","value":null}]`,
+			expected: `{"foo":null,"baz":"qux"}`,
+		},
+		{
+			name:     "adding an array element",
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":{"b":1,"a":2}}]`,
+			expected: `{"foo":["bar","qux","baz",{"b":1,"a":2}]}`,
+		},
+		{
+			name:     "removing an object member",
+			doc:      `{"baz":"qux","foo":"bar","abc":1}`,
+			patch:    `[{"op":"remove","path":"/baz"}]`,
+			expected: `{"foo":"bar","abc":1}`,
+		},
+		{
+			name:     "removing an array element",
+			doc:      `{"foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:     "replacing a value",
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name: "moving a value",
+			doc:  `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"},
+				{"op":"move","from":"/foo","path":"/foo"}]`,
+			expected: `{"qux":{"corge":"grault","thud":"fred"},"foo":{"bar":"baz"}}`,
+		},
+		{
+			name:     "moving an array element",
+			doc:      `{"foo":["all","grass","cows","eat"]}`,
+			patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:     "copying a value",
+			doc:      `{"foo":{"b":[1,2]},"bar":1}`,
+			patch:    `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"add","path":"/baz/b/-","value":3}]`,
+			expected: `{"foo":{"b":[1,2]},"bar":1,"baz":{"b":[1,2,3]}}`,
+		},
+		{
+			name: "testing values",
+			doc:  `{"baz":"qux","foo":["a",2,"c"],"obj":{"a":1,"b":2.0}}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},
+				{"op":"test","path":"/foo/1","value":2.0},
+				{"op":"test","path":"/obj","value":{"b":2,"a":1e0}}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"],"obj":{"a":1,"b":2.0}}`,
+		},
+		{
+			name:     "escaped pointers",
+			doc:      `{"a/b":1,"m~n":2}`,
+			patch:    `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`,
+			expected: `{"a/b":3}`,
+		},
+		{
+			name:     "replacing the whole document",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"","value":[1,2]}]`,
+			expected: `[1,2]`,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			patched, err := ApplyJSON([]byte(testCase.doc), []byte(testCase.patch))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, string(patched))
+		})
+	}
+}
+
+func TestApplyJSONInvalidInput(t *testing.T) {
+	for _, testCase := range []struct{ doc, patch string }{
+		{`{"a":1} trailing`, `[]`},
+		{`{"a":1}`, `[] trailing`},
+		{`{"a":1,}`, `[{"op":"remove","path":"/a"}]`},
+	} {
+		_, err := ApplyJSON([]byte(testCase.doc), []byte(testCase.patch))
+		assert.Error(t, err, testCase)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		patch    Patch
+		index    int
+		expected error
+	}{
+		{
+			name:     "missing member",
+			patch:    Patch{{Op: Remove, Path: "/foo/baz"}},
+			expected: ErrPathNotFound,
+		},
+		{
+			name:     "missing parent",
+			patch:    Patch{{Op: Add, Path: "/baz/bat", Value: 1}},
+			expected: ErrPathNotFound,
+		},
+		{
+			name:     "out of range index",
+			patch:    Patch{{Op: Add, Path: "/foo/bar/3", Value: 1}},
+			expected: ErrInvalidIndex,
+		},
+		{
+			name:     "index with leading zero",
+			patch:    Patch{{Op: Replace, Path: "/foo/bar/01", Value: 1}},
+			expected: ErrInvalidIndex,
+		},
+		{
+			name:     "invalid pointer",
+			patch:    Patch{{Op: Remove, Path: "foo"}},
+			expected: ErrInvalidPointer,
+		},
+		{
+			name:     "invalid escape",
+			patch:    Patch{{Op: Remove, Path: "/fo~2o"}},
+			expected: ErrInvalidPointer,
+		},
+		{
+			name:     "moving into a child",
+			patch:    Patch{{Op: Move, From: "/foo", Path: "/foo/baz"}},
+			expected: ErrInvalidMove,
+		},
+		{
+			name: "failed test",
+			patch: Patch{
+				{Op: Replace, Path: "/foo/bar/0", Value: 10},
+				{Op: Test, Path: "/foo/bar", Value: []any{1, 2}},
+			},
+			index:    1,
+			expected: ErrTestFailed,
+		},
+		{
+			name:     "unknown operation",
+			patch:    Patch{{Op: "frobnicate", Path: "/foo"}},
+			expected: ErrUnknownOperation,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			doc := decode(t, `{"foo":{"bar":[1,2]}}`)
+
+			_, err := Apply(doc, testCase.patch)
+			require.ErrorIs(t, err, testCase.expected)
+
+			var operationErr *OperationError
+			require.ErrorAs(t, err, &operationErr)
+			assert.Equal(t, testCase.index, operationErr.Index)
+
+			// the original document is left untouched
+			assertJSON(t, `{"foo":{"bar":[1,2]}}`, doc)
+		})
+	}
+}
+
+func TestOperationJSON(t *testing.T) {
+	patch := Patch{
+		{Op: Add, Path: "/a", Value: nil},
+		{Op: Remove, Path: "/b"},
+		{Op: Move, From: "/c", Path: "/d"},
+		{Op: Test, Path: "/e", Value: []any{"x"}},
+	}
+	expected := `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},` +
+		`{"op":"move","from":"/c","path":"/d"},{"op":"test","path":"/e","value":["x"]}]`
+
+	data, err := json.Marshal(patch)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+
+	var decoded Patch
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, patch, decoded)
+
+	t.Run("values are decoded as ordered documents", func(t *testing.T) {
+		var operation Operation
+		require.NoError(t, json.Unmarshal([]byte(`{"op":"add","path":"","value":{"b":1,"a":[2]}}`), &operation))
+		assertJSON(t, `{"b":1,"a":[2]}`, operation.Value)
+		assert.IsType(t, &Object{}, operation.Value)
+	})
+
+	for _, invalid := range []string{
+		`{"path":"/a"}`,
+		`{"op":"add","path":"/a"}`,
+		`{"op":"copy","path":"/a"}`,
+		`{"op":"remove","path":1}`,
+		`{"op":"frobnicate","path":"/a"}`,
+	} {
+		var operation Operation
+		assert.Error(t, json.Unmarshal([]byte(invalid), &operation), invalid)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		from     string
+		to       string
+		expected Patch
+	}{
+		{
+			name: "identical documents",
+			from: `{"a":1,"b":[1,{"c":2}]}`,
+			to:   `{"a":1,"b":[1,{"c":2}]}`,
+		},
+		{
+			name: "changed values",
+			from: `{"a":1,"b":{"c":[1,2,3],"d":"x"}}`,
+			to:   `{"a":2,"b":{"c":[1,5],"d":["x"]}}`,
+			expected: Patch{
+				{Op: Replace, Path: "/a", Value: json.Number("2")},
+				{Op: Replace, Path: "/b/c/1", Value: json.Number("5")},
+				{Op: Remove, Path: "/b/c/2"},
+				{Op: Replace, Path: "/b/d", Value: []any{"x"}},
+			},
+		},
+		{
+			name: "added and removed members",
+			from: `{"a":1,"b":2,"c":3}`,
+			to:   `{"a":1,"c":3,"d/e":4}`,
+			expected: Patch{
+				{Op: Remove, Path: "/b"},
+				{Op: Add, Path: "/d~1e", Value: json.Number("4")},
+			},
+		},
+		{
+			name: "order changes",
+			from: `{"a":1,"b":2,"c":3,"d":4}`,
+			to:   `{"b":2,"c":3,"a":1,"e":5,"d":4}`,
+			expected: Patch{
+				{Op: Move, From: "/a", Path: "/a"},
+				{Op: Add, Path: "/e", Value: json.Number("5")},
+				{Op: Move, From: "/d", Path: "/d"},
+			},
+		},
+		{
+			name: "nested order changes",
+			from: `[{"x":{"a":1,"b":2}},1]`,
+			to:   `[{"x":{"b":2,"a":1}},1,{"y":2}]`,
+			expected: Patch{
+				{Op: Move, From: "/0/x/a", Path: "/0/x/a"},
+				{Op: Add, Path: "/-", Value: decode(t, `{"y":2}`)},
+			},
+		},
+		{
+			name:     "different types",
+			from:     `{"a":1}`,
+			to:       `["a",1]`,
+			expected: Patch{{Op: Replace, Path: "", Value: []any{"a", json.Number("1")}}},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			from, to := decode(t, testCase.from), decode(t, testCase.to)
+
+			patch := Diff(from, to)
+			assert.Equal(t, testCase.expected, patch)
+
+			patched, err := Apply(from, patch)
+			require.NoError(t, err)
+			assertJSON(t, testCase.to, patched)
+		})
+	}
+}
+
+func TestDiffRoundTrip(t *testing.T) {
+	documents := []string{
+		`{}`,
+		`{"a":1,"b":{"c":[1,2],"d":null},"e":"f"}`,
+		`{"e":"f","b":{"d":null,"c":[2,1,0]},"a":1}`,
+		`{"z":true,"b":{"c":{"x":1},"g":[]},"a":1,"e":"f"}`,
+		`{"b":{"g":[{"k":1,"j":2}],"c":{"x":1}},"z":true}`,
+		`[1,{"a":1}]`,
+	}
+
+	for _, from := range documents {
+		for _, to := range documents {
+			patched, err := Apply(decode(t, from), Diff(decode(t, from), decode(t, to)))
+			require.NoError(t, err)
+			assertJSON(t, to, patched)
+		}
+	}
+}
+
+func decode(t *testing.T, data string) any {
+	t.Helper()
+	doc, err := orderedmap.UnmarshalNestedJSON([]byte(data))
+	require.NoError(t, err)
+	return doc
+}
+
+// assertJSON checks that doc's JSON encoding matches expected, including the order of objects' members.
+func assertJSON(t *testing.T, expected string, doc any) {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(data))
+	assert.Equal(t, compact(t, expected), string(data))
+}
+
+func compact(t *testing.T, data string) string {
+	t.Helper()
+	doc := decode(t, data)
+	encoded, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return string(encoded)
+}
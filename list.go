@@ -0,0 +1,133 @@
+package orderedmap
+
+// pairStore keeps track of the order of a map's pairs.
+// Its API mimics that of `container/list`.
+type pairStore[K comparable, V any] interface {
+	// Front returns the first pair, or nil if there are none.
+	Front() *Pair[K, V]
+	// Back returns the last pair, or nil if there are none.
+	Back() *Pair[K, V]
+	// Len returns the number of pairs.
+	Len() int
+	// PushBack inserts the pair, which must not be in the store already, at the back.
+	PushBack(pair *Pair[K, V])
+	// Remove removes the pair; its Next and Prev methods then return nil.
+	Remove(pair *Pair[K, V])
+	// MoveAfter moves the pair to its new position after mark. If pair and mark are the
+	// same, the store is not modified.
+	MoveAfter(pair, mark *Pair[K, V])
+	// MoveBefore moves the pair to its new position before mark. If pair and mark are the
+	// same, the store is not modified.
+	MoveBefore(pair, mark *Pair[K, V])
+	// MoveToBack moves the pair to the back.
+	MoveToBack(pair *Pair[K, V])
+	// MoveToFront moves the pair to the front.
+	MoveToFront(pair *Pair[K, V])
+}
+
+var (
+	_ pairStore[int, any] = &pairList[int, any]{}
+	_ pairStore[int, any] = &compactList[int, any]{}
+)
+
+// pairList is the default pairStore, an intrusive doubly linked list of pairs: the links live
+// directly in the Pair structs, so that adding a pair to the list doesn't require any extra allocation.
+type pairList[K comparable, V any] struct {
+	front, back *Pair[K, V]
+	len         int
+}
+
+// Front returns the first pair of the list, or nil if it's empty.
+func (l *pairList[K, V]) Front() *Pair[K, V] {
+	return l.front
+}
+
+// Back returns the last pair of the list, or nil if it's empty.
+func (l *pairList[K, V]) Back() *Pair[K, V] {
+	return l.back
+}
+
+// Len returns the number of pairs in the list.
+func (l *pairList[K, V]) Len() int {
+	return l.len
+}
+
+// PushBack inserts the pair, which must not be in the list already, at the back of the list.
+func (l *pairList[K, V]) PushBack(pair *Pair[K, V]) {
+	l.insertAfter(pair, l.back)
+}
+
+// Remove removes the pair from the list, and clears its links.
+func (l *pairList[K, V]) Remove(pair *Pair[K, V]) {
+	if pair.prev == nil {
+		l.front = pair.next
+	} else {
+		pair.prev.next = pair.next
+	}
+	if pair.next == nil {
+		l.back = pair.prev
+	} else {
+		pair.next.prev = pair.prev
+	}
+
+	pair.prev, pair.next = nil, nil
+	l.len--
+}
+
+// MoveAfter moves the pair to its new position after mark. If pair and mark are the
+// same, the list is not modified.
+func (l *pairList[K, V]) MoveAfter(pair, mark *Pair[K, V]) {
+	if pair == mark || mark.next == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAfter(pair, mark)
+}
+
+// MoveBefore moves the pair to its new position before mark. If pair and mark are the
+// same, the list is not modified.
+func (l *pairList[K, V]) MoveBefore(pair, mark *Pair[K, V]) {
+	if pair == mark || mark.prev == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAfter(pair, mark.prev)
+}
+
+// MoveToBack moves the pair to the back of the list.
+func (l *pairList[K, V]) MoveToBack(pair *Pair[K, V]) {
+	if l.back == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAfter(pair, l.back)
+}
+
+// MoveToFront moves the pair to the front of the list.
+func (l *pairList[K, V]) MoveToFront(pair *Pair[K, V]) {
+	if l.front == pair {
+		return
+	}
+	l.Remove(pair)
+	l.insertAfter(pair, nil)
+}
+
+// insertAfter inserts the pair, which must not be in the list already, after mark;
+// or at the front of the list if mark is nil.
+func (l *pairList[K, V]) insertAfter(pair, mark *Pair[K, V]) {
+	pair.prev = mark
+	if mark == nil {
+		pair.next = l.front
+		l.front = pair
+	} else {
+		pair.next = mark.next
+		mark.next = pair
+	}
+	if pair.next == nil {
+		l.back = pair
+	} else {
+		pair.next.prev = pair
+	}
+
+	l.len++
+}
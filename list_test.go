@@ -0,0 +1,150 @@
+package orderedmap
+
+import (
+	"container/list"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletedPairsAreUnlinked(t *testing.T) {
+	om := New[int, int]()
+	om.Set(1, 1)
+	om.Set(2, 2)
+	om.Set(3, 3)
+
+	pair := om.GetPair(2)
+	om.Delete(2)
+
+	assert.Nil(t, pair.Next())
+	assert.Nil(t, pair.Prev())
+	assertOrderedPairsEqual(t, om, []int{1, 3}, []int{1, 3})
+}
+
+// elementListMap is a minimal ordered map laid out the way OrderedMap used to be prior to
+// switching to an intrusive list, i.e. with a separately allocated list element per pair,
+// to benchmark the two against each other. It uses the standard library's `container/list`,
+// whose only difference with the generic list previously used is an extra type assertion
+// when iterating.
+type elementListMap[K comparable, V any] struct {
+	pairs map[K]*elementListPair[K, V]
+	list  *list.List
+}
+
+type elementListPair[K comparable, V any] struct {
+	Key   K
+	Value V
+
+	element *list.Element
+}
+
+func newElementListMap[K comparable, V any]() *elementListMap[K, V] {
+	return &elementListMap[K, V]{
+		pairs: make(map[K]*elementListPair[K, V]),
+		list:  list.New(),
+	}
+}
+
+func (m *elementListMap[K, V]) Set(key K, value V) {
+	if pair, present := m.pairs[key]; present {
+		pair.Value = value
+		return
+	}
+
+	pair := &elementListPair[K, V]{Key: key, Value: value}
+	pair.element = m.list.PushBack(pair)
+	m.pairs[key] = pair
+}
+
+func (m *elementListMap[K, V]) Delete(key K) {
+	if pair, present := m.pairs[key]; present {
+		m.list.Remove(pair.element)
+		delete(m.pairs, key)
+	}
+}
+
+const benchmarkMapSize = 10000
+
+func BenchmarkSet(b *testing.B) {
+	b.Run("intrusive list", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			om := New[int, int]()
+			for j := 0; j < benchmarkMapSize; j++ {
+				om.Set(j, j)
+			}
+		}
+	})
+
+	b.Run("element per pair", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := newElementListMap[int, int]()
+			for j := 0; j < benchmarkMapSize; j++ {
+				m.Set(j, j)
+			}
+		}
+	})
+}
+
+func BenchmarkSetAndDelete(b *testing.B) {
+	b.Run("intrusive list", func(b *testing.B) {
+		om := New[int, int]()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			om.Set(i, i)
+			if i >= benchmarkMapSize {
+				om.Delete(i - benchmarkMapSize)
+			}
+		}
+	})
+
+	b.Run("element per pair", func(b *testing.B) {
+		m := newElementListMap[int, int]()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Set(i, i)
+			if i >= benchmarkMapSize {
+				m.Delete(i - benchmarkMapSize)
+			}
+		}
+	})
+}
+
+// The intrusive list must be at least as fast as the element-per-pair list it replaced: in
+// particular, Pair.Next must not look up the map, e.g. to check whether it's in checked mode.
+func BenchmarkIterateFromOldest(b *testing.B) {
+	b.Run("intrusive list", func(b *testing.B) {
+		om := New[int, int]()
+		for j := 0; j < benchmarkMapSize; j++ {
+			om.Set(j, j)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+				sum += pair.Value
+			}
+		}
+	})
+
+	b.Run("element per pair", func(b *testing.B) {
+		m := newElementListMap[int, int]()
+		for j := 0; j < benchmarkMapSize; j++ {
+			m.Set(j, j)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for element := m.list.Front(); element != nil; element = element.Next() {
+				sum += element.Value.(*elementListPair[int, int]).Value
+			}
+		}
+	})
+}
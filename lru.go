@@ -0,0 +1,129 @@
+package orderedmap
+
+import "iter"
+
+// LRU is a bounded cache that evicts its least recently used entries once it holds more than
+// its maximum number of entries. It is built on top of an OrderedMap, where the oldest pair is
+// the least recently used one.
+//
+// All operations are constant-time. Like OrderedMap, LRU is not safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	om       *OrderedMap[K, V]
+	capacity int
+	onEvict  func(evicted Pair[K, V])
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// LRUStats are the counters maintained by an LRU.
+type LRUStats struct {
+	// Hits is the number of calls to Get that found the requested key.
+	Hits uint64
+	// Misses is the number of calls to Get that didn't find the requested key.
+	Misses uint64
+	// Evictions is the number of entries evicted to make room for new ones.
+	Evictions uint64
+}
+
+const invalidLRUCapacityMessage = `orderedmap.NewLRU[K,V]() requires a strictly positive capacity`
+
+// NewLRU creates a new LRU holding at most capacity entries. onEvict, if non-nil,
+// is called with every entry evicted to make room for a new one; it is not called
+// on explicit calls to Delete.
+// Panics if capacity is not strictly positive.
+func NewLRU[K comparable, V any](capacity int, onEvict func(evicted Pair[K, V])) *LRU[K, V] {
+	if capacity <= 0 {
+		panic(invalidLRUCapacityMessage)
+	}
+
+	return &LRU[K, V]{
+		// the capacity is a limit, not an expected size: don't pre-allocate for it
+		om:       New[K, V](),
+		capacity: capacity,
+		onEvict:  onEvict,
+	}
+}
+
+// Get looks for the given key, and returns the value associated with it,
+// or V's nil value if not found. The boolean it returns says whether the key is present in the cache.
+// If found, the entry becomes the most recently used one.
+func (c *LRU[K, V]) Get(key K) (val V, present bool) {
+	val, err := c.om.GetAndMoveToBack(key)
+	if err != nil {
+		c.misses++
+		return
+	}
+
+	c.hits++
+	return val, true
+}
+
+// Peek is the same as Get, except that it doesn't make the entry the most recently used one,
+// nor does it update the hit and miss counters.
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	return c.om.Get(key)
+}
+
+// Set sets the key-value pair, and makes it the most recently used entry.
+// If that makes the cache go over capacity, the least recently used entry is evicted.
+// Returns whether an entry was evicted.
+func (c *LRU[K, V]) Set(key K, value V) (evicted bool) {
+	if pair := c.om.GetPair(key); pair != nil {
+		pair.Value = value
+		_ = c.om.MoveToBack(key)
+		return false
+	}
+
+	c.om.Set(key, value)
+	if c.om.Len() <= c.capacity {
+		return false
+	}
+
+	oldest := c.om.Oldest()
+	c.om.Delete(oldest.Key)
+	c.evictions++
+	if c.onEvict != nil {
+		c.onEvict(Pair[K, V]{Key: oldest.Key, Value: oldest.Value})
+	}
+
+	return true
+}
+
+// Delete removes the key-value pair, and returns what `Peek` would have returned
+// on that key prior to the call to `Delete`.
+func (c *LRU[K, V]) Delete(key K) (V, bool) {
+	return c.om.Delete(key)
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRU[K, V]) Len() int {
+	return c.om.Len()
+}
+
+// Capacity returns the maximum number of entries in the cache.
+func (c *LRU[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Stats returns the cache's hit, miss and eviction counters.
+func (c *LRU[K, V]) Stats() LRUStats {
+	return LRUStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
+
+// FromLeastRecent returns an iterator over all the key-value pairs in the cache,
+// starting from the least recently used one. Iterating doesn't change the entries' recency.
+func (c *LRU[K, V]) FromLeastRecent() iter.Seq2[K, V] {
+	return c.om.FromOldest()
+}
+
+// FromMostRecent returns an iterator over all the key-value pairs in the cache,
+// starting from the most recently used one. Iterating doesn't change the entries' recency.
+func (c *LRU[K, V]) FromMostRecent() iter.Seq2[K, V] {
+	return c.om.FromNewest()
+}
@@ -0,0 +1,107 @@
+package orderedmap
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	var evicted []Pair[string, int]
+	cache := NewLRU[string, int](3, func(pair Pair[string, int]) {
+		evicted = append(evicted, pair)
+	})
+	assert.Equal(t, 3, cache.Capacity())
+
+	assert.False(t, cache.Set("a", 1))
+	assert.False(t, cache.Set("b", 2))
+	assert.False(t, cache.Set("c", 3))
+	assertLRUEqual(t, cache, []string{"a", "b", "c"}, []int{1, 2, 3})
+
+	// a becomes the most recently used
+	value, present := cache.Get("a")
+	assert.True(t, present)
+	assert.Equal(t, 1, value)
+	assertLRUEqual(t, cache, []string{"b", "c", "a"}, []int{2, 3, 1})
+
+	// so b gets evicted
+	assert.True(t, cache.Set("d", 4))
+	assertLRUEqual(t, cache, []string{"c", "a", "d"}, []int{3, 1, 4})
+	assert.Equal(t, []Pair[string, int]{{Key: "b", Value: 2}}, evicted)
+
+	// updating doesn't evict, but promotes
+	assert.False(t, cache.Set("c", 30))
+	assertLRUEqual(t, cache, []string{"a", "d", "c"}, []int{1, 4, 30})
+
+	// peeking doesn't promote
+	value, present = cache.Peek("a")
+	assert.True(t, present)
+	assert.Equal(t, 1, value)
+	assertLRUEqual(t, cache, []string{"a", "d", "c"}, []int{1, 4, 30})
+
+	value, present = cache.Get("b")
+	assert.False(t, present)
+	assert.Equal(t, 0, value)
+
+	// explicit deletions don't trigger the callback
+	value, present = cache.Delete("d")
+	assert.True(t, present)
+	assert.Equal(t, 4, value)
+	assertLRUEqual(t, cache, []string{"a", "c"}, []int{1, 30})
+	assert.Len(t, evicted, 1)
+
+	assert.Equal(t, LRUStats{Hits: 1, Misses: 1, Evictions: 1}, cache.Stats())
+}
+
+func TestLRUWithoutCallback(t *testing.T) {
+	cache := NewLRU[int, int](1, nil)
+
+	assert.False(t, cache.Set(1, 1))
+	assert.True(t, cache.Set(2, 2))
+	assert.True(t, cache.Set(3, 3))
+	assertLRUEqual(t, cache, []int{3}, []int{3})
+	assert.Equal(t, LRUStats{Evictions: 2}, cache.Stats())
+}
+
+func TestLRUInvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		assert.PanicsWithValue(t, invalidLRUCapacityMessage, func() {
+			_ = NewLRU[int, int](capacity, nil)
+		})
+	}
+}
+
+func TestLRUHugeCapacity(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	cache := NewLRU[int, int](10_000_000, nil)
+	runtime.ReadMemStats(&after)
+
+	// an empty cache doesn't allocate for its whole capacity
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+
+	cache = NewLRU[int, int](math.MaxInt, nil)
+	assert.False(t, cache.Set(1, 1))
+	assertLRUEqual(t, cache, []int{1}, []int{1})
+}
+
+func assertLRUEqual[K comparable, V any](t *testing.T, cache *LRU[K, V], expectedKeys []K, expectedValues []V) {
+	t.Helper()
+
+	assert.Equal(t, len(expectedKeys), cache.Len())
+	assertOrderedPairsEqual(t, cache.om, expectedKeys, expectedValues)
+
+	i := len(expectedKeys) - 1
+	for k, v := range cache.FromMostRecent() {
+		assert.Equal(t, expectedKeys[i], k)
+		assert.Equal(t, expectedValues[i], v)
+		i--
+	}
+	i = 0
+	for k := range cache.FromLeastRecent() {
+		assert.Equal(t, expectedKeys[i], k)
+		i++
+	}
+}
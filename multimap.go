@@ -0,0 +1,355 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"iter"
+	"slices"
+
+	"github.com/buger/jsonparser"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// OrderedMultiMap is an ordered map where each key can be associated with several values,
+// e.g. to represent HTTP headers or query strings. It keeps track of the global order in which
+// entries were added, across all keys.
+//
+// Entries are represented by MultiMapEntry handles; walking them with Next and Prev follows the
+// global order.
+//
+// Its zero value is only usable to unmarshal into; instances must otherwise be created with NewMultiMap.
+type OrderedMultiMap[K comparable, V any] struct {
+	entries      map[K][]*Pair[K, V]
+	list         pairList[K, V]
+	jsonEncoding MultiMapJSONEncoding
+}
+
+var (
+	_ json.Marshaler   = &OrderedMultiMap[int, any]{}
+	_ json.Unmarshaler = &OrderedMultiMap[int, any]{}
+)
+
+// MultiMapJSONEncoding determines how an OrderedMultiMap is marshalled to, and unmarshalled from, JSON.
+type MultiMapJSONEncoding int
+
+const (
+	// GroupedValues encodes each key once, with an array of all its values, e.g. {"a":[1,3],"b":[2]}.
+	// Keys are ordered by their first entry. This is the default encoding.
+	GroupedValues MultiMapJSONEncoding = iota
+	// RepeatedKeys encodes each entry as its own object member, in the global order, repeating
+	// keys as needed, e.g. {"a":1,"b":2,"a":3}. That preserves the order of all entries, but not
+	// all JSON parsers support duplicate keys.
+	RepeatedKeys
+)
+
+// MultiMapOption configures an OrderedMultiMap.
+type MultiMapOption[K comparable, V any] func(m *OrderedMultiMap[K, V])
+
+// WithMultiMapJSONEncoding sets the MultiMapJSONEncoding; the default is GroupedValues.
+func WithMultiMapJSONEncoding[K comparable, V any](encoding MultiMapJSONEncoding) MultiMapOption[K, V] {
+	return func(m *OrderedMultiMap[K, V]) {
+		m.jsonEncoding = encoding
+	}
+}
+
+// NewMultiMap creates a new OrderedMultiMap.
+func NewMultiMap[K comparable, V any](options ...MultiMapOption[K, V]) *OrderedMultiMap[K, V] {
+	m := &OrderedMultiMap[K, V]{
+		entries: make(map[K][]*Pair[K, V]),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Add adds a new entry at the back of the map, even if key is already present, and returns it.
+// The returned entry can be used to update its value, or passed to DeleteEntry.
+func (m *OrderedMultiMap[K, V]) Add(key K, value V) MultiMapEntry[K, V] {
+	if m.entries == nil {
+		m.entries = make(map[K][]*Pair[K, V])
+	}
+
+	pair := &Pair[K, V]{
+		Key:   key,
+		Value: value,
+	}
+	m.list.PushBack(pair)
+	m.entries[key] = append(m.entries[key], pair)
+
+	return MultiMapEntry[K, V]{pair: pair, m: m}
+}
+
+// Get returns the value of key's oldest entry. The boolean it returns says whether the key is present.
+func (m *OrderedMultiMap[K, V]) Get(key K) (val V, present bool) {
+	if entries := m.entries[key]; len(entries) != 0 {
+		return entries[0].Value, true
+	}
+	return
+}
+
+// GetAll returns the values of all of key's entries, from oldest to newest; or nil if key is
+// not present.
+func (m *OrderedMultiMap[K, V]) GetAll(key K) []V {
+	entries := m.entries[key]
+	if len(entries) == 0 {
+		return nil
+	}
+
+	values := make([]V, len(entries))
+	for i, entry := range entries {
+		values[i] = entry.Value
+	}
+	return values
+}
+
+// GetEntries returns all of key's entries, from oldest to newest; or nil if key is not present.
+func (m *OrderedMultiMap[K, V]) GetEntries(key K) []MultiMapEntry[K, V] {
+	pairs := m.entries[key]
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	entries := make([]MultiMapEntry[K, V], len(pairs))
+	for i, pair := range pairs {
+		entries[i] = MultiMapEntry[K, V]{pair: pair, m: m}
+	}
+	return entries
+}
+
+// Delete removes all of key's entries, and returns how many there were.
+func (m *OrderedMultiMap[K, V]) Delete(key K) int {
+	entries := m.entries[key]
+	for _, entry := range entries {
+		m.list.Remove(entry)
+	}
+	delete(m.entries, key)
+	return len(entries)
+}
+
+// DeleteEntry removes a single entry, as returned by Add, GetEntries or when walking the map,
+// and returns true; or returns false if the entry is not in the map.
+func (m *OrderedMultiMap[K, V]) DeleteEntry(entry MultiMapEntry[K, V]) bool {
+	if entry.m != m {
+		return false
+	}
+
+	key := entry.pair.Key
+	entries := m.entries[key]
+	index := slices.Index(entries, entry.pair)
+	if index == -1 {
+		return false
+	}
+
+	m.list.Remove(entry.pair)
+	if len(entries) == 1 {
+		delete(m.entries, key)
+	} else {
+		m.entries[key] = slices.Delete(entries, index, index+1)
+	}
+
+	return true
+}
+
+// Count returns the number of key's entries.
+func (m *OrderedMultiMap[K, V]) Count(key K) int {
+	return len(m.entries[key])
+}
+
+// Len returns the total number of entries in the map.
+func (m *OrderedMultiMap[K, V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.list.Len()
+}
+
+// KeyLen returns the number of distinct keys in the map.
+func (m *OrderedMultiMap[K, V]) KeyLen() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.entries)
+}
+
+// Oldest returns the oldest entry. The boolean it returns is false iff the map is empty.
+// It's meant to be used to iterate on the map's entries from the oldest to the newest, e.g.:
+// for entry, ok := m.Oldest(); ok; entry, ok = entry.Next() { fmt.Println(entry.Key()) }
+func (m *OrderedMultiMap[K, V]) Oldest() (MultiMapEntry[K, V], bool) {
+	return m.entryOf(m.oldest())
+}
+
+// Newest returns the newest entry. The boolean it returns is false iff the map is empty.
+func (m *OrderedMultiMap[K, V]) Newest() (MultiMapEntry[K, V], bool) {
+	return m.entryOf(m.newest())
+}
+
+func (m *OrderedMultiMap[K, V]) oldest() *Pair[K, V] {
+	if m == nil {
+		return nil
+	}
+	return m.list.Front()
+}
+
+func (m *OrderedMultiMap[K, V]) newest() *Pair[K, V] {
+	if m == nil {
+		return nil
+	}
+	return m.list.Back()
+}
+
+func (m *OrderedMultiMap[K, V]) entryOf(pair *Pair[K, V]) (MultiMapEntry[K, V], bool) {
+	if pair == nil {
+		return MultiMapEntry[K, V]{}, false
+	}
+	return MultiMapEntry[K, V]{pair: pair, m: m}, true
+}
+
+// FromOldest returns an iterator over all the entries in the map, starting from the oldest one.
+func (m *OrderedMultiMap[K, V]) FromOldest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := m.oldest(); entry != nil; entry = entry.Next() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// FromNewest returns an iterator over all the entries in the map, starting from the newest one.
+func (m *OrderedMultiMap[K, V]) FromNewest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := m.newest(); entry != nil; entry = entry.Prev() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the map's distinct keys, ordered by their oldest entry.
+func (m *OrderedMultiMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for entry := m.oldest(); entry != nil; entry = entry.Next() {
+			if m.isFirstEntry(entry) && !yield(entry.Key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *OrderedMultiMap[K, V]) isFirstEntry(entry *Pair[K, V]) bool {
+	return m.entries[entry.Key][0] == entry
+}
+
+// MarshalJSON implements the json.Marshaler interface, using the map's MultiMapJSONEncoding.
+func (m *OrderedMultiMap[K, V]) MarshalJSON() ([]byte, error) {
+	if m == nil || m.entries == nil {
+		return []byte("null"), nil
+	}
+
+	var writer jwriter.Writer
+	writer.RawByte('{')
+
+	firstMember := true
+	for entry := m.oldest(); entry != nil; entry = entry.Next() {
+		if m.jsonEncoding == GroupedValues && !m.isFirstEntry(entry) {
+			continue
+		}
+
+		if firstMember {
+			firstMember = false
+		} else {
+			writer.RawByte(',')
+		}
+
+		if err := writeJSONKey(&writer, entry.Key); err != nil {
+			return nil, err
+		}
+		writer.RawByte(':')
+
+		// the errors are checked at the end of the function
+		if m.jsonEncoding == GroupedValues {
+			writer.Raw(json.Marshal(m.GetAll(entry.Key)))
+		} else {
+			writer.Raw(json.Marshal(entry.Value))
+		}
+	}
+
+	writer.RawByte('}')
+
+	return dumpWriter(&writer)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, using the map's MultiMapJSONEncoding.
+// Decoded entries are added to the ones already in the map, if any.
+func (m *OrderedMultiMap[K, V]) UnmarshalJSON(data []byte) error {
+	return jsonparser.ObjectEach(
+		data,
+		func(keyData []byte, valueData []byte, dataType jsonparser.ValueType, offset int) error {
+			valueData = rawJSONValue(data, valueData, dataType, offset)
+
+			key, err := decodeJSONKey[K](keyData)
+			if err != nil {
+				return err
+			}
+
+			if m.jsonEncoding == RepeatedKeys {
+				var value V
+				if err := json.Unmarshal(valueData, &value); err != nil {
+					return err
+				}
+				m.Add(key, value)
+				return nil
+			}
+
+			var values []V
+			if err := json.Unmarshal(valueData, &values); err != nil {
+				return err
+			}
+			for _, value := range values {
+				m.Add(key, value)
+			}
+			return nil
+		})
+}
+
+// MultiMapEntry is a handle on an entry of an OrderedMultiMap: unlike with a *Pair, its key can't be
+// changed, which would corrupt the map. Its value can still be updated through ValuePtr.
+// Entries are comparable, e.g. to look for a given entry in the slice returned by GetEntries.
+// The zero MultiMapEntry doesn't point to any entry, and must not be used.
+type MultiMapEntry[K comparable, V any] struct {
+	pair *Pair[K, V]
+	m    *OrderedMultiMap[K, V]
+}
+
+// Key returns the entry's key.
+func (e MultiMapEntry[K, V]) Key() K {
+	return e.pair.Key
+}
+
+// Value returns the entry's value.
+func (e MultiMapEntry[K, V]) Value() V {
+	return e.pair.Value
+}
+
+// ValuePtr returns a pointer to the entry's value, allowing to update it in place.
+func (e MultiMapEntry[K, V]) ValuePtr() *V {
+	return &e.pair.Value
+}
+
+// Next returns the next entry, in the global order. The boolean it returns is false iff this is
+// the newest entry.
+func (e MultiMapEntry[K, V]) Next() (MultiMapEntry[K, V], bool) {
+	return e.m.entryOf(e.pair.Next())
+}
+
+// Prev returns the previous entry, in the global order. The boolean it returns is false iff this is
+// the oldest entry.
+func (e MultiMapEntry[K, V]) Prev() (MultiMapEntry[K, V], bool) {
+	return e.m.entryOf(e.pair.Prev())
+}
+
+// IsDetached returns true iff the entry is no longer in its map, e.g. because it's been deleted.
+func (e MultiMapEntry[K, V]) IsDetached() bool {
+	return !slices.Contains(e.m.entries[e.pair.Key], e.pair)
+}
@@ -0,0 +1,157 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMultiMap(t *testing.T) {
+	m := NewMultiMap[string, int]()
+	m.Add("a", 1)
+	m.Add("b", 2)
+	entry := m.Add("a", 3)
+	m.Add("c", 4)
+	m.Add("a", 5)
+
+	assert.Equal(t, 5, m.Len())
+	assert.Equal(t, 3, m.KeyLen())
+	assert.Equal(t, 3, m.Count("a"))
+	assert.Equal(t, 0, m.Count("i dont exist"))
+
+	value, present := m.Get("a")
+	assert.True(t, present)
+	assert.Equal(t, 1, value)
+	_, present = m.Get("i dont exist")
+	assert.False(t, present)
+
+	assert.Equal(t, []int{1, 3, 5}, m.GetAll("a"))
+	assert.Nil(t, m.GetAll("i dont exist"))
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()))
+
+	assertSeq2Equal(t, m.FromOldest(),
+		[]string{"a", "b", "a", "c", "a"},
+		[]int{1, 2, 3, 4, 5})
+	assertSeq2Equal(t, m.FromNewest(),
+		[]string{"a", "c", "a", "b", "a"},
+		[]int{5, 4, 3, 2, 1})
+
+	*entry.ValuePtr() = 30
+	assert.Equal(t, []int{1, 30, 5}, m.GetAll("a"))
+	assert.False(t, entry.IsDetached())
+	assert.True(t, m.DeleteEntry(entry))
+	assert.True(t, entry.IsDetached())
+	assert.False(t, m.DeleteEntry(entry))
+	assert.Equal(t, []int{1, 5}, m.GetAll("a"))
+	assertSeq2Equal(t, m.FromOldest(),
+		[]string{"a", "b", "c", "a"},
+		[]int{1, 2, 4, 5})
+
+	// the keys' order follows their oldest entry
+	oldest, ok := m.Oldest()
+	require.True(t, ok)
+	assert.True(t, m.DeleteEntry(oldest))
+	assert.Equal(t, []string{"b", "c", "a"}, slices.Collect(m.Keys()))
+
+	assert.True(t, m.DeleteEntry(m.GetEntries("b")[0]))
+	assert.Equal(t, 0, m.Count("b"))
+	assert.Equal(t, 2, m.KeyLen())
+
+	m.Add("c", 6)
+	assert.Equal(t, 2, m.Delete("c"))
+	assert.Equal(t, 0, m.Delete("c"))
+	assertSeq2Equal(t, m.FromOldest(), []string{"a"}, []int{5})
+	assert.Equal(t, 1, m.KeyLen())
+
+	assert.Equal(t, 1, m.Delete("a"))
+	assertSeq2Equal[string, int](t, m.FromOldest(), nil, nil)
+	assert.Equal(t, 0, m.Len())
+	_, ok = m.Oldest()
+	assert.False(t, ok)
+	_, ok = m.Newest()
+	assert.False(t, ok)
+}
+
+func TestOrderedMultiMapEntries(t *testing.T) {
+	m := NewMultiMap[string, int]()
+	m.Add("a", 1)
+	m.Add("b", 2)
+	m.Add("a", 3)
+
+	var keys []string
+	var values []int
+	for entry, ok := m.Oldest(); ok; entry, ok = entry.Next() {
+		keys = append(keys, entry.Key())
+		values = append(values, entry.Value())
+	}
+	assert.Equal(t, []string{"a", "b", "a"}, keys)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	newest, ok := m.Newest()
+	require.True(t, ok)
+	prev, ok := newest.Prev()
+	require.True(t, ok)
+	assert.Equal(t, "b", prev.Key())
+	assert.True(t, m.GetEntries("a")[1] == newest)
+
+	// entries of other maps aren't deleted, even if they have the same keys
+	other := NewMultiMap[string, int]()
+	otherEntry := other.Add("a", 1)
+	assert.False(t, m.DeleteEntry(otherEntry))
+	assert.False(t, otherEntry.IsDetached())
+	assert.Equal(t, 2, m.Count("a"))
+
+	assert.Equal(t, 2, m.Delete("a"))
+	assert.True(t, newest.IsDetached())
+	assert.Nil(t, m.GetEntries("a"))
+}
+
+func TestOrderedMultiMapJSON(t *testing.T) {
+	newMultiMap := func(options ...MultiMapOption[string, int]) *OrderedMultiMap[string, int] {
+		m := NewMultiMap(options...)
+		m.Add("a", 1)
+		m.Add("b", 2)
+		m.Add("a", 3)
+		return m
+	}
+
+	t.Run("grouped values", func(t *testing.T) {
+		data, err := json.Marshal(newMultiMap())
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":[1,3],"b":[2]}`, string(data))
+
+		var decoded OrderedMultiMap[string, int]
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assertSeq2Equal(t, decoded.FromOldest(), []string{"a", "a", "b"}, []int{1, 3, 2})
+
+		assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &decoded))
+	})
+
+	t.Run("repeated keys", func(t *testing.T) {
+		encoding := WithMultiMapJSONEncoding[string, int](RepeatedKeys)
+
+		data, err := json.Marshal(newMultiMap(encoding))
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":2,"a":3}`, string(data))
+
+		decoded := NewMultiMap(encoding)
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assertSeq2Equal(t, decoded.FromOldest(), []string{"a", "b", "a"}, []int{1, 2, 3})
+
+		assert.Error(t, json.Unmarshal([]byte(`{"a":[1]}`), decoded))
+	})
+
+	t.Run("empty and nil", func(t *testing.T) {
+		data, err := json.Marshal(NewMultiMap[string, int]())
+		require.NoError(t, err)
+		assert.Equal(t, `{}`, string(data))
+
+		var nilMap *OrderedMultiMap[string, int]
+		data, err = json.Marshal(nilMap)
+		require.NoError(t, err)
+		assert.Equal(t, `null`, string(data))
+	})
+}
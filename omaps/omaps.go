@@ -0,0 +1,85 @@
+// Package omaps defines various functions useful with ordered maps, akin to what
+// the standard library's maps package offers for regular maps. Unlike their
+// standard counterparts, all of them preserve the maps' order.
+package omaps
+
+import (
+	"iter"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Insert adds the key-value pairs from seq to om, in order. It's equivalent to calling
+// Set on each pair sequentially: new keys are appended at the back of the map, and
+// whether existing keys are moved depends on the map's OrderingPolicy.
+func Insert[K comparable, V any](om *orderedmap.OrderedMap[K, V], seq iter.Seq2[K, V]) {
+	for k, v := range seq {
+		om.Set(k, v)
+	}
+}
+
+// Collect creates a new OrderedMap from an iterator over pairs.
+// If a key appears more than once, its last value is kept at its first position.
+func Collect[K comparable, V any](seq iter.Seq[orderedmap.Pair[K, V]]) *orderedmap.OrderedMap[K, V] {
+	om := orderedmap.New[K, V]()
+	for pair := range seq {
+		om.Set(pair.Key, pair.Value)
+	}
+	return om
+}
+
+// Keys returns the map's keys, from oldest to newest.
+func Keys[K comparable, V any](om *orderedmap.OrderedMap[K, V]) []K {
+	keys := make([]K, 0, om.Len())
+	for k := range om.KeysFromOldest() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns the map's values, from oldest to newest.
+func Values[K comparable, V any](om *orderedmap.OrderedMap[K, V]) []V {
+	values := make([]V, 0, om.Len())
+	for v := range om.ValuesFromOldest() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// DeleteFunc deletes all the pairs for which del returns true.
+// It's the opposite of OrderedMap.Filter, which keeps those pairs.
+func DeleteFunc[K comparable, V any](om *orderedmap.OrderedMap[K, V], del func(K, V) bool) {
+	om.Filter(func(k K, v V) bool {
+		return !del(k, v)
+	})
+}
+
+// CopyPolicy determines where Copy puts pairs whose keys are already present in the destination map.
+type CopyPolicy int
+
+const (
+	// KeepPosition leaves keys already present in the destination map at their current position,
+	// regardless of the destination map's OrderingPolicy.
+	KeepPosition CopyPolicy = iota
+	// MoveToBack moves keys already present in the destination map to its back, so that the
+	// destination map ends with all of the source map's pairs, in the same order.
+	MoveToBack
+)
+
+// Copy copies all the key-value pairs from src to dst, from oldest to newest. When a key
+// from src is already in dst, its value is overwritten, and its position depends on policy.
+// New keys are appended at the back of dst.
+func Copy[K comparable, V any](dst, src *orderedmap.OrderedMap[K, V], policy CopyPolicy) {
+	for pair := src.Oldest(); pair != nil; pair = pair.Next() {
+		dstPair := dst.GetPair(pair.Key)
+		if dstPair == nil {
+			dst.Set(pair.Key, pair.Value)
+			continue
+		}
+
+		dstPair.Value = pair.Value
+		if policy == MoveToBack {
+			_ = dst.MoveToBack(pair.Key)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package omaps
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func newTestMap() *orderedmap.OrderedMap[string, int] {
+	om := orderedmap.New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	return om
+}
+
+func TestInsert(t *testing.T) {
+	om := newTestMap()
+
+	Insert(om, maps.All(map[string]int{"d": 4}))
+	Insert(om, func(yield func(string, int) bool) {
+		_ = yield("b", 20) && yield("e", 5)
+	})
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, Keys(om))
+	assert.Equal(t, []int{1, 20, 3, 4, 5}, Values(om))
+}
+
+func TestCollect(t *testing.T) {
+	om := Collect(slices.Values([]orderedmap.Pair[string, int]{
+		{Key: "c", Value: 3},
+		{Key: "a", Value: 1},
+		{Key: "c", Value: 30},
+		{Key: "b", Value: 2},
+	}))
+
+	assert.Equal(t, []string{"c", "a", "b"}, Keys(om))
+	assert.Equal(t, []int{30, 1, 2}, Values(om))
+}
+
+func TestKeysAndValues(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, Keys(newTestMap()))
+	assert.Equal(t, []int{1, 2, 3}, Values(newTestMap()))
+
+	empty := orderedmap.New[string, int]()
+	assert.Equal(t, []string{}, Keys(empty))
+	assert.Equal(t, []int{}, Values(empty))
+}
+
+func TestDeleteFunc(t *testing.T) {
+	om := newTestMap()
+
+	DeleteFunc(om, func(k string, v int) bool {
+		return k == "a" || v == 3
+	})
+
+	assert.Equal(t, []string{"b"}, Keys(om))
+	assert.Equal(t, []int{2}, Values(om))
+}
+
+func TestCopy(t *testing.T) {
+	src := orderedmap.New[string, int]()
+	src.Set("d", 4)
+	src.Set("a", 10)
+	src.Set("e", 5)
+
+	t.Run("keeping positions", func(t *testing.T) {
+		dst := newTestMap()
+		Copy(dst, src, KeepPosition)
+
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, Keys(dst))
+		assert.Equal(t, []int{10, 2, 3, 4, 5}, Values(dst))
+	})
+
+	t.Run("keeping positions regardless of the destination's ordering policy", func(t *testing.T) {
+		dst := orderedmap.New[string, int](orderedmap.WithOrderingPolicy[string, int](orderedmap.WriteOrder))
+		Copy(dst, newTestMap(), KeepPosition)
+		Copy(dst, src, KeepPosition)
+
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, Keys(dst))
+		assert.Equal(t, []int{10, 2, 3, 4, 5}, Values(dst))
+	})
+
+	t.Run("moving to back", func(t *testing.T) {
+		dst := newTestMap()
+		Copy(dst, src, MoveToBack)
+
+		assert.Equal(t, []string{"b", "c", "d", "a", "e"}, Keys(dst))
+		assert.Equal(t, []int{2, 3, 4, 10, 5}, Values(dst))
+	})
+}
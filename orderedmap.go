@@ -1,7 +1,8 @@
 // Package orderedmap implements an ordered map, i.e. a map that also keeps track of
 // the order in which keys were inserted.
 //
-// All operations are constant-time.
+// All operations are constant-time, unless the map is created with options trading
+// some of that for other features, see WithIndexing and WithCompactStorage.
 //
 // Github repo: https://github.com/wk8/go-ordered-map
 package orderedmap
@@ -9,27 +10,53 @@ package orderedmap
 import (
 	"fmt"
 	"iter"
-
-	list "github.com/bahlo/generic-list-go"
 )
 
 type Pair[K comparable, V any] struct {
 	Key   K
 	Value V
 
-	element *list.Element[*Pair[K, V]]
+	prev, next *Pair[K, V]
+	// only set when the map is indexed, see WithIndexing
+	node *indexNode[K, V]
+	// only set when the map uses compact storage, see WithCompactStorage
+	compact  *compactList[K, V]
+	position int
+	// whether the pair is currently in a map, see IsDetached
+	attached bool
+	// whether the pair's map was created WithCheckedIterators; stored on the pair itself so that
+	// Next and Prev don't need to look up the map
+	checked bool
 }
 
 type OrderedMap[K comparable, V any] struct {
 	pairs             map[K]*Pair[K, V]
-	list              *list.List[*Pair[K, V]]
+	list              pairStore[K, V]
 	disableHTMLEscape bool
+	orderingPolicy    OrderingPolicy
+	index             *orderIndex[K, V]
+	maxTombstoneRatio float64
+	// incremented on every structural change, see WithCheckedIterators
+	modCount uint64
+	checked  bool
+	// see WithNestedJSONDecoding
+	nestedJSONDecoding bool
+	duplicateKeyPolicy DuplicateKeyPolicy
+	// nil for compact JSON, see WithJSONIndent
+	jsonIndentation *jsonIndentation
 }
 
 type initConfig[K comparable, V any] struct {
-	capacity          int
-	initialData       []Pair[K, V]
-	disableHTMLEscape bool
+	capacity           int
+	initialData        []Pair[K, V]
+	disableHTMLEscape  bool
+	orderingPolicy     OrderingPolicy
+	indexed            bool
+	maxTombstoneRatio  float64
+	checked            bool
+	nestedJSONDecoding bool
+	duplicateKeyPolicy DuplicateKeyPolicy
+	jsonIndentation    *jsonIndentation
 }
 
 type InitOption[K comparable, V any] func(config *initConfig[K, V])
@@ -58,6 +85,32 @@ func WithDisableHTMLEscape[K comparable, V any]() InitOption[K, V] {
 	}
 }
 
+// OrderingPolicy determines how reading or updating keys already present in the map affects
+// their position.
+type OrderingPolicy int
+
+const (
+	// InsertionOrder keeps pairs in the order in which their keys were first inserted:
+	// updating an existing key doesn't change its position. This is the default policy.
+	InsertionOrder OrderingPolicy = iota
+	// WriteOrder keeps pairs in the order in which they were last written:
+	// Set, Store and AddPairs move existing keys to the back of the map.
+	WriteOrder
+	// AccessOrder keeps pairs in the order in which they were last accessed, akin to Java's
+	// access-ordered LinkedHashMap: on top of what WriteOrder does, Get, Load and Value also
+	// move the pair to the back of the map. Note that this means that these methods then
+	// modify the map.
+	// GetPair, Oldest, Newest and iterators don't change the map's order.
+	AccessOrder
+)
+
+// WithOrderingPolicy sets the map's OrderingPolicy; the default is InsertionOrder.
+func WithOrderingPolicy[K comparable, V any](policy OrderingPolicy) InitOption[K, V] {
+	return func(c *initConfig[K, V]) {
+		c.orderingPolicy = policy
+	}
+}
+
 // New creates a new OrderedMap.
 // options can either be one or several InitOption[K, V], or a single integer,
 // which is then interpreted as a capacity hint, à la make(map[K]V, capacity).
@@ -86,7 +139,16 @@ func New[K comparable, V any](options ...any) *OrderedMap[K, V] {
 		}
 	}
 
+	orderedMap.maxTombstoneRatio = config.maxTombstoneRatio
 	orderedMap.initialize(config.capacity, config.disableHTMLEscape)
+	orderedMap.orderingPolicy = config.orderingPolicy
+	orderedMap.checked = config.checked
+	orderedMap.nestedJSONDecoding = config.nestedJSONDecoding
+	orderedMap.duplicateKeyPolicy = config.duplicateKeyPolicy
+	orderedMap.jsonIndentation = config.jsonIndentation
+	if config.indexed {
+		orderedMap.index = &orderIndex[K, V]{}
+	}
 	orderedMap.AddPairs(config.initialData...)
 
 	return orderedMap
@@ -97,15 +159,28 @@ const invalidOptionMessage = `when using orderedmap.New[K,V]() with options, eit
 func invalidOption() { panic(invalidOptionMessage) }
 
 func (om *OrderedMap[K, V]) initialize(capacity int, disableHTMLEscape bool) {
+	for _, pair := range om.pairs {
+		pair.attached = false
+	}
 	om.pairs = make(map[K]*Pair[K, V], capacity)
-	om.list = list.New[*Pair[K, V]]()
+	if om.maxTombstoneRatio > 0 {
+		om.list = newCompactList[K, V](capacity, om.maxTombstoneRatio)
+	} else {
+		om.list = &pairList[K, V]{}
+	}
 	om.disableHTMLEscape = disableHTMLEscape
+	if om.index != nil {
+		om.index = &orderIndex[K, V]{}
+	}
+	om.modCount++
 }
 
 // Get looks for the given key, and returns the value associated with it,
 // or V's nil value if not found. The boolean it returns says whether the key is present in the map.
+// With the AccessOrder policy, the pair is moved to the back of the map.
 func (om *OrderedMap[K, V]) Get(key K) (val V, present bool) {
 	if pair, present := om.pairs[key]; present {
+		om.read(pair)
 		return pair.Value, true
 	}
 
@@ -118,10 +193,9 @@ func (om *OrderedMap[K, V]) Load(key K) (V, bool) {
 }
 
 // Value returns the value associated with the given key or the zero value.
+// With the AccessOrder policy, the pair is moved to the back of the map.
 func (om *OrderedMap[K, V]) Value(key K) (val V) {
-	if pair, present := om.pairs[key]; present {
-		val = pair.Value
-	}
+	val, _ = om.Get(key)
 	return
 }
 
@@ -134,21 +208,51 @@ func (om *OrderedMap[K, V]) GetPair(key K) *Pair[K, V] {
 
 // Set sets the key-value pair, and returns what `Get` would have returned
 // on that key prior to the call to `Set`.
+// Updating an existing key doesn't change its position, unless the map's
+// ordering policy is WriteOrder or AccessOrder, in which case it is moved to the back.
 func (om *OrderedMap[K, V]) Set(key K, value V) (val V, present bool) {
 	if pair, present := om.pairs[key]; present {
 		oldValue := pair.Value
-		pair.Value = value
+		om.write(pair, value)
 		return oldValue, true
 	}
 
+	om.insert(key, value)
+
+	return
+}
+
+// read and write apply the map's OrderingPolicy to existing pairs being read or written.
+
+func (om *OrderedMap[K, V]) read(pair *Pair[K, V]) {
+	if om.orderingPolicy == AccessOrder {
+		om.moveToBack(pair)
+	}
+}
+
+func (om *OrderedMap[K, V]) write(pair *Pair[K, V], value V) {
+	pair.Value = value
+	if om.orderingPolicy != InsertionOrder {
+		om.moveToBack(pair)
+	}
+}
+
+// insert adds a new pair at the back of the map; the key must not be present already.
+func (om *OrderedMap[K, V]) insert(key K, value V) *Pair[K, V] {
 	pair := &Pair[K, V]{
-		Key:   key,
-		Value: value,
+		Key:      key,
+		Value:    value,
+		attached: true,
+		checked:  om.checked,
+	}
+	om.list.PushBack(pair)
+	if om.index != nil {
+		om.index.pushBack(pair)
 	}
-	pair.element = om.list.PushBack(pair)
 	om.pairs[key] = pair
+	om.modCount++
 
-	return
+	return pair
 }
 
 // AddPairs allows setting multiple pairs at a time. It's equivalent to calling
@@ -168,8 +272,7 @@ func (om *OrderedMap[K, V]) Store(key K, value V) (V, bool) {
 // on that key prior to the call to `Delete`.
 func (om *OrderedMap[K, V]) Delete(key K) (val V, present bool) {
 	if pair, present := om.pairs[key]; present {
-		om.list.Remove(pair.element)
-		delete(om.pairs, key)
+		om.remove(pair)
 		return pair.Value, true
 	}
 	return
@@ -190,7 +293,7 @@ func (om *OrderedMap[K, V]) Oldest() *Pair[K, V] {
 	if om == nil || om.list == nil {
 		return nil
 	}
-	return listElementToPair(om.list.Front())
+	return om.list.Front()
 }
 
 // Newest returns a pointer to the newest pair. It's meant to be used to iterate on the ordered map's
@@ -200,24 +303,82 @@ func (om *OrderedMap[K, V]) Newest() *Pair[K, V] {
 	if om == nil || om.list == nil {
 		return nil
 	}
-	return listElementToPair(om.list.Back())
+	return om.list.Back()
 }
 
 // Next returns a pointer to the next pair.
+// If the map was created WithCheckedIterators, it panics if the pair has been deleted from the map.
 func (p *Pair[K, V]) Next() *Pair[K, V] {
-	return listElementToPair(p.element.Next())
+	p.checkAttached()
+	if p.compact != nil {
+		return p.compact.next(p)
+	}
+	return p.next
 }
 
 // Prev returns a pointer to the previous pair.
+// If the map was created WithCheckedIterators, it panics if the pair has been deleted from the map.
 func (p *Pair[K, V]) Prev() *Pair[K, V] {
-	return listElementToPair(p.element.Prev())
+	p.checkAttached()
+	if p.compact != nil {
+		return p.compact.prev(p)
+	}
+	return p.prev
 }
 
-func listElementToPair[K comparable, V any](element *list.Element[*Pair[K, V]]) *Pair[K, V] {
-	if element == nil {
-		return nil
+// the following methods perform all the structural changes to the map's order,
+// keeping its index, if any, in sync.
+
+func (om *OrderedMap[K, V]) remove(pair *Pair[K, V]) {
+	pair.attached = false
+	om.list.Remove(pair)
+	if om.index != nil {
+		om.index.remove(pair)
+	}
+	delete(om.pairs, pair.Key)
+	om.modCount++
+}
+
+func (om *OrderedMap[K, V]) moveAfter(pair, mark *Pair[K, V]) {
+	if pair == mark {
+		return
+	}
+	om.list.MoveAfter(pair, mark)
+	om.modCount++
+	if om.index != nil {
+		om.index.detach(pair)
+		om.index.insertAt(pair, om.index.rank(mark)+1)
+	}
+}
+
+func (om *OrderedMap[K, V]) moveBefore(pair, mark *Pair[K, V]) {
+	if pair == mark {
+		return
+	}
+	om.list.MoveBefore(pair, mark)
+	om.modCount++
+	if om.index != nil {
+		om.index.detach(pair)
+		om.index.insertAt(pair, om.index.rank(mark))
+	}
+}
+
+func (om *OrderedMap[K, V]) moveToBack(pair *Pair[K, V]) {
+	om.list.MoveToBack(pair)
+	om.modCount++
+	if om.index != nil {
+		om.index.detach(pair)
+		om.index.pushBack(pair)
+	}
+}
+
+func (om *OrderedMap[K, V]) moveToFront(pair *Pair[K, V]) {
+	om.list.MoveToFront(pair)
+	om.modCount++
+	if om.index != nil {
+		om.index.detach(pair)
+		om.index.insertAt(pair, 0)
 	}
-	return element.Value
 }
 
 // KeyNotFoundError may be returned by functions in this package when they're called with keys that are not present
@@ -234,11 +395,11 @@ func (e *KeyNotFoundError[K]) Error() string {
 // Returns an error iff key or markKey are not present in the map. If an error is returned,
 // it will be a KeyNotFoundError.
 func (om *OrderedMap[K, V]) MoveAfter(key, markKey K) error {
-	elements, err := om.getElements(key, markKey)
+	pairs, err := om.getPairs(key, markKey)
 	if err != nil {
 		return err
 	}
-	om.list.MoveAfter(elements[0], elements[1])
+	om.moveAfter(pairs[0], pairs[1])
 	return nil
 }
 
@@ -246,24 +407,77 @@ func (om *OrderedMap[K, V]) MoveAfter(key, markKey K) error {
 // Returns an error iff key or markKey are not present in the map. If an error is returned,
 // it will be a KeyNotFoundError.
 func (om *OrderedMap[K, V]) MoveBefore(key, markKey K) error {
-	elements, err := om.getElements(key, markKey)
+	pairs, err := om.getPairs(key, markKey)
 	if err != nil {
 		return err
 	}
-	om.list.MoveBefore(elements[0], elements[1])
+	om.moveBefore(pairs[0], pairs[1])
 	return nil
 }
 
-func (om *OrderedMap[K, V]) getElements(keys ...K) ([]*list.Element[*Pair[K, V]], error) {
-	elements := make([]*list.Element[*Pair[K, V]], len(keys))
+// SetAfter sets the key-value pair, and moves it to its new position after the one associated with markKey,
+// whether key was already present or not. It returns what `Get` would have returned on key prior to the call.
+// Returns an error iff markKey is not present in the map, in which case the map is left untouched. If an error
+// is returned, it will be a KeyNotFoundError.
+func (om *OrderedMap[K, V]) SetAfter(markKey, key K, value V) (val V, present bool, err error) {
+	return om.setRelativeTo(markKey, key, value, om.moveAfter)
+}
+
+// SetBefore sets the key-value pair, and moves it to its new position before the one associated with markKey,
+// whether key was already present or not. It returns what `Get` would have returned on key prior to the call.
+// Returns an error iff markKey is not present in the map, in which case the map is left untouched. If an error
+// is returned, it will be a KeyNotFoundError.
+func (om *OrderedMap[K, V]) SetBefore(markKey, key K, value V) (val V, present bool, err error) {
+	return om.setRelativeTo(markKey, key, value, om.moveBefore)
+}
+
+func (om *OrderedMap[K, V]) setRelativeTo(
+	markKey, key K, value V, move func(pair, mark *Pair[K, V]),
+) (val V, present bool, err error) {
+	mark, markPresent := om.pairs[markKey]
+	if !markPresent {
+		err = &KeyNotFoundError[K]{markKey}
+		return
+	}
+
+	pair := om.pairs[key]
+	if pair == nil {
+		pair = om.insert(key, value)
+	} else {
+		val, present = pair.Value, true
+		pair.Value = value
+	}
+	move(pair, mark)
+
+	return
+}
+
+// SetFront sets the key-value pair, and moves it to the front of the ordered map, i.e. makes it
+// the oldest pair in the map, whether key was already present or not. It returns what `Get` would have
+// returned on key prior to the call.
+func (om *OrderedMap[K, V]) SetFront(key K, value V) (val V, present bool) {
+	pair := om.pairs[key]
+	if pair == nil {
+		pair = om.insert(key, value)
+	} else {
+		val, present = pair.Value, true
+		pair.Value = value
+	}
+	om.moveToFront(pair)
+
+	return
+}
+
+func (om *OrderedMap[K, V]) getPairs(keys ...K) ([]*Pair[K, V], error) {
+	pairs := make([]*Pair[K, V], len(keys))
 	for i, k := range keys {
 		pair, present := om.pairs[k]
 		if !present {
 			return nil, &KeyNotFoundError[K]{k}
 		}
-		elements[i] = pair.element
+		pairs[i] = pair
 	}
-	return elements, nil
+	return pairs, nil
 }
 
 // MoveToBack moves the value associated with key to the back of the ordered map,
@@ -289,7 +503,7 @@ func (om *OrderedMap[K, V]) MoveToFront(key K) error {
 func (om *OrderedMap[K, V]) GetAndMoveToBack(key K) (val V, err error) {
 	if pair, present := om.pairs[key]; present {
 		val = pair.Value
-		om.list.MoveToBack(pair.element)
+		om.moveToBack(pair)
 	} else {
 		err = &KeyNotFoundError[K]{key}
 	}
@@ -302,7 +516,7 @@ func (om *OrderedMap[K, V]) GetAndMoveToBack(key K) (val V, err error) {
 func (om *OrderedMap[K, V]) GetAndMoveToFront(key K) (val V, err error) {
 	if pair, present := om.pairs[key]; present {
 		val = pair.Value
-		om.list.MoveToFront(pair.element)
+		om.moveToFront(pair)
 	} else {
 		err = &KeyNotFoundError[K]{key}
 	}
@@ -313,7 +527,7 @@ func (om *OrderedMap[K, V]) GetAndMoveToFront(key K) (val V, err error) {
 // FromOldest returns an iterator over all the key-value pairs in the map, starting from the oldest pair.
 func (om *OrderedMap[K, V]) FromOldest() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		for pair := range om.pairsFromOldest() {
 			if !yield(pair.Key, pair.Value) {
 				return
 			}
@@ -324,7 +538,7 @@ func (om *OrderedMap[K, V]) FromOldest() iter.Seq2[K, V] {
 // FromNewest returns an iterator over all the key-value pairs in the map, starting from the newest pair.
 func (om *OrderedMap[K, V]) FromNewest() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+		for pair := range om.pairsFromNewest() {
 			if !yield(pair.Key, pair.Value) {
 				return
 			}
@@ -335,7 +549,7 @@ func (om *OrderedMap[K, V]) FromNewest() iter.Seq2[K, V] {
 // KeysFromOldest returns an iterator over all the keys in the map, starting from the oldest pair.
 func (om *OrderedMap[K, V]) KeysFromOldest() iter.Seq[K] {
 	return func(yield func(K) bool) {
-		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		for pair := range om.pairsFromOldest() {
 			if !yield(pair.Key) {
 				return
 			}
@@ -346,7 +560,7 @@ func (om *OrderedMap[K, V]) KeysFromOldest() iter.Seq[K] {
 // KeysFromNewest returns an iterator over all the keys in the map, starting from the newest pair.
 func (om *OrderedMap[K, V]) KeysFromNewest() iter.Seq[K] {
 	return func(yield func(K) bool) {
-		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+		for pair := range om.pairsFromNewest() {
 			if !yield(pair.Key) {
 				return
 			}
@@ -357,7 +571,7 @@ func (om *OrderedMap[K, V]) KeysFromNewest() iter.Seq[K] {
 // ValuesFromOldest returns an iterator over all the values in the map, starting from the oldest pair.
 func (om *OrderedMap[K, V]) ValuesFromOldest() iter.Seq[V] {
 	return func(yield func(V) bool) {
-		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+		for pair := range om.pairsFromOldest() {
 			if !yield(pair.Value) {
 				return
 			}
@@ -368,7 +582,7 @@ func (om *OrderedMap[K, V]) ValuesFromOldest() iter.Seq[V] {
 // ValuesFromNewest returns an iterator over all the values in the map, starting from the newest pair.
 func (om *OrderedMap[K, V]) ValuesFromNewest() iter.Seq[V] {
 	return func(yield func(V) bool) {
-		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+		for pair := range om.pairsFromNewest() {
 			if !yield(pair.Value) {
 				return
 			}
@@ -376,6 +590,48 @@ func (om *OrderedMap[K, V]) ValuesFromNewest() iter.Seq[V] {
 	}
 }
 
+// pairsFromOldest and pairsFromNewest are the iterators underlying all others. They behave
+// the same as walking the map with Next or Prev, but compact storage is walked by position,
+// which avoids having to dereference each pair to find the next one.
+
+func (om *OrderedMap[K, V]) pairsFromOldest() iter.Seq[*Pair[K, V]] {
+	return func(yield func(*Pair[K, V]) bool) {
+		yield = om.checkedYield(yield)
+		if compact, ok := om.compactList(); ok {
+			compact.forward(yield)
+			return
+		}
+		for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+			if !yield(pair) {
+				return
+			}
+		}
+	}
+}
+
+func (om *OrderedMap[K, V]) pairsFromNewest() iter.Seq[*Pair[K, V]] {
+	return func(yield func(*Pair[K, V]) bool) {
+		yield = om.checkedYield(yield)
+		if compact, ok := om.compactList(); ok {
+			compact.backward(yield)
+			return
+		}
+		for pair := om.Newest(); pair != nil; pair = pair.Prev() {
+			if !yield(pair) {
+				return
+			}
+		}
+	}
+}
+
+func (om *OrderedMap[K, V]) compactList() (*compactList[K, V], bool) {
+	if om == nil {
+		return nil, false
+	}
+	compact, ok := om.list.(*compactList[K, V])
+	return compact, ok
+}
+
 // From creates a new OrderedMap from an iterator over key-value pairs.
 func From[K comparable, V any](i iter.Seq2[K, V]) *OrderedMap[K, V] {
 	oMap := New[K, V]()
@@ -387,7 +643,8 @@ func From[K comparable, V any](i iter.Seq2[K, V]) *OrderedMap[K, V] {
 	return oMap
 }
 
-func (om *OrderedMap[K, V]) Filter(predicate func (K, V) bool) {
+// Filter deletes all the pairs for which predicate returns false.
+func (om *OrderedMap[K, V]) Filter(predicate func(K, V) bool) {
 	for pair := om.Oldest(); pair != nil; {
 		key, value := pair.Key, pair.Value
 		pair = pair.Next()
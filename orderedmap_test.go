@@ -514,3 +514,139 @@ func TestFilter(t *testing.T) {
 		assert.True(t, k%3==0)
 	}
 }
+
+func TestOrderingPolicies(t *testing.T) {
+	newMap := func(policy OrderingPolicy) *OrderedMap[string, int] {
+		om := New[string, int](WithOrderingPolicy[string, int](policy))
+		om.Set("a", 1)
+		om.Set("b", 2)
+		om.Set("c", 3)
+		om.Set("d", 4)
+		return om
+	}
+
+	t.Run("insertion order", func(t *testing.T) {
+		om := newMap(InsertionOrder)
+
+		om.Set("b", 20)
+		om.Get("a")
+		om.Value("c")
+		om.Load("a")
+
+		assertOrderedPairsEqual(t, om,
+			[]string{"a", "b", "c", "d"},
+			[]int{1, 20, 3, 4})
+	})
+
+	t.Run("write order", func(t *testing.T) {
+		om := newMap(WriteOrder)
+
+		oldValue, present := om.Set("b", 20)
+		assert.Equal(t, 2, oldValue)
+		assert.True(t, present)
+		om.Store("a", 10)
+		om.AddPairs(Pair[string, int]{Key: "c", Value: 30})
+		om.Get("b")
+		om.Value("d")
+
+		assertOrderedPairsEqual(t, om,
+			[]string{"d", "b", "a", "c"},
+			[]int{4, 20, 10, 30})
+	})
+
+	t.Run("access order", func(t *testing.T) {
+		om := newMap(AccessOrder)
+
+		value, present := om.Get("a")
+		assert.Equal(t, 1, value)
+		assert.True(t, present)
+		assertOrderedPairsEqual(t, om,
+			[]string{"b", "c", "d", "a"},
+			[]int{2, 3, 4, 1})
+
+		assert.Equal(t, 2, om.Value("b"))
+		assertOrderedPairsEqual(t, om,
+			[]string{"c", "d", "a", "b"},
+			[]int{3, 4, 1, 2})
+
+		value, present = om.Load("d")
+		assert.Equal(t, 4, value)
+		assert.True(t, present)
+		om.Set("c", 30)
+		assertOrderedPairsEqual(t, om,
+			[]string{"a", "b", "d", "c"},
+			[]int{1, 2, 4, 30})
+
+		// neither missing keys, GetPair nor iterating change the order
+		_, present = om.Get("i dont exist")
+		assert.False(t, present)
+		assert.Equal(t, 1, om.GetPair("a").Value)
+		for range om.FromOldest() {
+		}
+		assertOrderedPairsEqual(t, om,
+			[]string{"a", "b", "d", "c"},
+			[]int{1, 2, 4, 30})
+	})
+}
+
+func TestSetRelative(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			om := newMaybeIndexed[int, any](indexed)
+			om.Set(1, "bar")
+			om.Set(2, 28)
+			om.Set(3, 100)
+
+			oldValue, present, err := om.SetAfter(1, 4, "baz")
+			assert.Nil(t, err)
+			assert.Nil(t, oldValue)
+			assert.False(t, present)
+			assertOrderedPairsEqual(t, om,
+				[]int{1, 4, 2, 3},
+				[]any{"bar", "baz", 28, 100})
+
+			oldValue, present, err = om.SetBefore(2, 3, 101)
+			assert.Nil(t, err)
+			assert.Equal(t, 100, oldValue)
+			assert.True(t, present)
+			assertOrderedPairsEqual(t, om,
+				[]int{1, 4, 3, 2},
+				[]any{"bar", "baz", 101, 28})
+
+			oldValue, present, err = om.SetAfter(2, 1, "foo")
+			assert.Nil(t, err)
+			assert.Equal(t, "bar", oldValue)
+			assert.True(t, present)
+			assertOrderedPairsEqual(t, om,
+				[]int{4, 3, 2, 1},
+				[]any{"baz", 101, 28, "foo"})
+
+			// using a key as its own mark just updates it
+			oldValue, present, err = om.SetBefore(3, 3, 102)
+			assert.Nil(t, err)
+			assert.Equal(t, 101, oldValue)
+			assert.True(t, present)
+			assertOrderedPairsEqual(t, om,
+				[]int{4, 3, 2, 1},
+				[]any{"baz", 102, 28, "foo"})
+
+			oldValue, present = om.SetFront(5, "28")
+			assert.Nil(t, oldValue)
+			assert.False(t, present)
+			oldValue, present = om.SetFront(2, 29)
+			assert.Equal(t, 28, oldValue)
+			assert.True(t, present)
+			assertOrderedPairsEqual(t, om,
+				[]int{2, 5, 4, 3, 1},
+				[]any{29, "28", "baz", 102, "foo"})
+
+			_, _, err = om.SetAfter(100, 6, "nope")
+			assert.Equal(t, &KeyNotFoundError[int]{100}, err)
+			_, _, err = om.SetBefore(100, 1, "nope")
+			assert.Equal(t, &KeyNotFoundError[int]{100}, err)
+			assertOrderedPairsEqual(t, om,
+				[]int{2, 5, 4, 3, 1},
+				[]any{29, "28", "baz", 102, "foo"})
+		})
+	}
+}
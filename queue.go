@@ -0,0 +1,141 @@
+package orderedmap
+
+import (
+	"context"
+	"sync"
+)
+
+// PopOldest removes the oldest pair from the map, and returns it; or returns nil if the map is empty.
+func (om *OrderedMap[K, V]) PopOldest() *Pair[K, V] {
+	pair := om.Oldest()
+	if pair != nil {
+		om.remove(pair)
+	}
+	return pair
+}
+
+// PopNewest removes the newest pair from the map, and returns it; or returns nil if the map is empty.
+func (om *OrderedMap[K, V]) PopNewest() *Pair[K, V] {
+	pair := om.Newest()
+	if pair != nil {
+		om.remove(pair)
+	}
+	return pair
+}
+
+// PopOldestN removes the n oldest pairs from the map, and returns them from oldest to newest.
+// If the map contains fewer than n pairs, all of them are removed and returned.
+func (om *OrderedMap[K, V]) PopOldestN(n int) []*Pair[K, V] {
+	return popN(n, om.Len(), om.PopOldest)
+}
+
+// PopNewestN removes the n newest pairs from the map, and returns them from newest to oldest.
+// If the map contains fewer than n pairs, all of them are removed and returned.
+func (om *OrderedMap[K, V]) PopNewestN(n int) []*Pair[K, V] {
+	return popN(n, om.Len(), om.PopNewest)
+}
+
+func popN[K comparable, V any](n, length int, pop func() *Pair[K, V]) []*Pair[K, V] {
+	n = max(min(n, length), 0)
+	pairs := make([]*Pair[K, V], n)
+	for i := range pairs {
+		pairs[i] = pop()
+	}
+	return pairs
+}
+
+// BlockingQueue is a thread-safe keyed queue: pairs are de-duplicated by key, and popped in order.
+// Popping from an empty queue blocks until a pair is pushed, or the context is done.
+type BlockingQueue[K comparable, V any] struct {
+	mutex sync.Mutex
+	om    *OrderedMap[K, V]
+	// closed and reset whenever a pair is pushed, to wake up blocked pops
+	pushed chan struct{}
+}
+
+// NewBlockingQueue creates a new BlockingQueue. It accepts the same options as New.
+func NewBlockingQueue[K comparable, V any](options ...any) *BlockingQueue[K, V] {
+	return &BlockingQueue[K, V]{
+		om: New[K, V](options...),
+	}
+}
+
+// Push sets the key-value pair, and returns what the queue held for that key prior to the call.
+// As with OrderedMap.Set, whether updating an existing key changes its position depends on
+// the queue's OrderingPolicy; by default, it doesn't.
+func (q *BlockingQueue[K, V]) Push(key K, value V) (V, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.pushed != nil {
+		close(q.pushed)
+		q.pushed = nil
+	}
+
+	return q.om.Set(key, value)
+}
+
+// Delete removes the key-value pair, and returns what the queue held for that key prior to the call.
+func (q *BlockingQueue[K, V]) Delete(key K) (V, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.om.Delete(key)
+}
+
+// Len returns the number of pairs in the queue.
+func (q *BlockingQueue[K, V]) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.om.Len()
+}
+
+// TryPopOldest is the same as PopOldest, but returns nil right away if the queue is empty.
+func (q *BlockingQueue[K, V]) TryPopOldest() *Pair[K, V] {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.om.PopOldest()
+}
+
+// TryPopNewest is the same as PopNewest, but returns nil right away if the queue is empty.
+func (q *BlockingQueue[K, V]) TryPopNewest() *Pair[K, V] {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.om.PopNewest()
+}
+
+// PopOldest removes the oldest pair from the queue, and returns it. If the queue is empty,
+// it blocks until a pair is pushed, or ctx is done, in which case it returns ctx's error.
+func (q *BlockingQueue[K, V]) PopOldest(ctx context.Context) (*Pair[K, V], error) {
+	return q.pop(ctx, q.om.PopOldest)
+}
+
+// PopNewest removes the newest pair from the queue, and returns it. If the queue is empty,
+// it blocks until a pair is pushed, or ctx is done, in which case it returns ctx's error.
+func (q *BlockingQueue[K, V]) PopNewest(ctx context.Context) (*Pair[K, V], error) {
+	return q.pop(ctx, q.om.PopNewest)
+}
+
+func (q *BlockingQueue[K, V]) pop(ctx context.Context, pop func() *Pair[K, V]) (*Pair[K, V], error) {
+	for {
+		q.mutex.Lock()
+		if pair := pop(); pair != nil {
+			q.mutex.Unlock()
+			return pair, nil
+		}
+		if q.pushed == nil {
+			q.pushed = make(chan struct{})
+		}
+		pushed := q.pushed
+		q.mutex.Unlock()
+
+		select {
+		case <-pushed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
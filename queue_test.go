@@ -0,0 +1,127 @@
+package orderedmap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPop(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, 10*i)
+	}
+
+	pair := om.PopOldest()
+	if assert.NotNil(t, pair) {
+		assert.Equal(t, 0, pair.Key)
+		assert.Equal(t, 0, pair.Value)
+	}
+	pair = om.PopNewest()
+	if assert.NotNil(t, pair) {
+		assert.Equal(t, 9, pair.Key)
+		assert.Equal(t, 90, pair.Value)
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, pairKeys(om.PopOldestN(3)))
+	assert.Equal(t, []int{8, 7}, pairKeys(om.PopNewestN(2)))
+	assert.Empty(t, om.PopOldestN(0))
+	assert.Empty(t, om.PopNewestN(-1))
+	assertOrderedPairsEqual(t, om, []int{4, 5, 6}, []int{40, 50, 60})
+
+	assert.Equal(t, []int{4, 5, 6}, pairKeys(om.PopOldestN(28)))
+	assertLenEqual(t, om, 0)
+
+	assert.Nil(t, om.PopOldest())
+	assert.Nil(t, om.PopNewest())
+	assert.Empty(t, om.PopNewestN(3))
+}
+
+func TestBlockingQueue(t *testing.T) {
+	q := NewBlockingQueue[string, int]()
+
+	_, present := q.Push("a", 1)
+	assert.False(t, present)
+	q.Push("b", 2)
+	q.Push("c", 3)
+	// de-duplicated by key
+	oldValue, present := q.Push("a", 10)
+	assert.True(t, present)
+	assert.Equal(t, 1, oldValue)
+	assert.Equal(t, 3, q.Len())
+
+	pair, err := q.PopOldest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "a", pair.Key)
+	assert.Equal(t, 10, pair.Value)
+
+	pair, err = q.PopNewest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "c", pair.Key)
+
+	value, present := q.Delete("b")
+	assert.True(t, present)
+	assert.Equal(t, 2, value)
+
+	assert.Nil(t, q.TryPopOldest())
+	assert.Nil(t, q.TryPopNewest())
+	q.Push("d", 4)
+	assert.Equal(t, "d", q.TryPopNewest().Key)
+}
+
+func TestBlockingQueueBlocks(t *testing.T) {
+	q := NewBlockingQueue[int, int]()
+
+	t.Run("until a pair is pushed", func(t *testing.T) {
+		consumers, n := 4, 100
+
+		var wg sync.WaitGroup
+		wg.Add(consumers)
+		popped := make(chan int, n)
+		for c := 0; c < consumers; c++ {
+			go func() {
+				defer wg.Done()
+				for i := 0; i < n/consumers; i++ {
+					pair, err := q.PopOldest(context.Background())
+					if assert.NoError(t, err) {
+						popped <- pair.Key
+					}
+				}
+			}()
+		}
+
+		for i := 0; i < n; i++ {
+			q.Push(i, i)
+		}
+		wg.Wait()
+		close(popped)
+
+		seen := make(map[int]bool)
+		for key := range popped {
+			seen[key] = true
+		}
+		assert.Len(t, seen, n)
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("until the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		pair, err := q.PopNewest(ctx)
+		assert.Nil(t, pair)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func pairKeys[K comparable, V any](pairs []*Pair[K, V]) []K {
+	keys := make([]K, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return keys
+}
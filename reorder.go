@@ -0,0 +1,83 @@
+package orderedmap
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// Reverse reverses the order of the map's pairs in place.
+func (om *OrderedMap[K, V]) Reverse() {
+	pairs := om.pairSlice()
+	slices.Reverse(pairs)
+	om.reorder(pairs)
+}
+
+// Swap swaps the positions of the pairs associated with key1 and key2.
+// Returns an error iff key1 or key2 are not present in the map. If an error is returned,
+// it will be a KeyNotFoundError.
+func (om *OrderedMap[K, V]) Swap(key1, key2 K) error {
+	pairs, err := om.getPairs(key1, key2)
+	if err != nil {
+		return err
+	}
+	pair1, pair2 := pairs[0], pairs[1]
+	if pair1 == pair2 {
+		return nil
+	}
+
+	next1 := pair1.Next()
+	if next1 == pair2 {
+		om.moveAfter(pair1, pair2)
+		return nil
+	}
+
+	om.moveBefore(pair1, pair2)
+	if next1 == nil {
+		om.moveToBack(pair2)
+	} else {
+		om.moveBefore(pair2, next1)
+	}
+
+	return nil
+}
+
+// RotateLeft rotates the map's pairs n positions towards the front: the n oldest pairs
+// become the newest ones, in the same order. A negative n rotates towards the back.
+// Runs in time linear to the length of the map.
+func (om *OrderedMap[K, V]) RotateLeft(n int) {
+	length := om.Len()
+	if length == 0 {
+		return
+	}
+
+	n %= length
+	if n < 0 {
+		n += length
+	}
+	for ; n > 0; n-- {
+		om.moveToBack(om.Oldest())
+	}
+}
+
+// RotateRight rotates the map's pairs n positions towards the back: the n newest pairs
+// become the oldest ones, in the same order. A negative n rotates towards the front.
+// Runs in time linear to the length of the map.
+func (om *OrderedMap[K, V]) RotateRight(n int) {
+	// reducing n first avoids overflowing when negating math.MinInt
+	om.RotateLeft(-(n % max(om.Len(), 1)))
+}
+
+// Shuffle pseudo-randomizes the order of the map's pairs, using randomness from source.
+// If source is nil, the default source from `math/rand/v2` is used.
+func (om *OrderedMap[K, V]) Shuffle(source rand.Source) {
+	pairs := om.pairSlice()
+
+	swap := func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] }
+	if source == nil {
+		rand.Shuffle(len(pairs), swap)
+	} else {
+		rand.New(source).Shuffle(len(pairs), swap) //nolint:gosec
+	}
+
+	om.reorder(pairs)
+}
@@ -0,0 +1,131 @@
+package orderedmap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverse(t *testing.T) {
+	om := New[int, string]()
+	om.Reverse()
+	assertLenEqual(t, om, 0)
+
+	om.Set(1, "a")
+	om.Set(2, "b")
+	om.Set(3, "c")
+	om.Reverse()
+	assertOrderedPairsEqual(t, om,
+		[]int{3, 2, 1},
+		[]string{"c", "b", "a"})
+}
+
+func TestSwap(t *testing.T) {
+	for _, testCase := range []struct {
+		name         string
+		key1, key2   int
+		expectedKeys []int
+	}{
+		{name: "same key", key1: 3, key2: 3, expectedKeys: []int{1, 2, 3, 4, 5}},
+		{name: "far apart", key1: 2, key2: 4, expectedKeys: []int{1, 4, 3, 2, 5}},
+		{name: "far apart, reversed", key1: 4, key2: 2, expectedKeys: []int{1, 4, 3, 2, 5}},
+		{name: "adjacent", key1: 2, key2: 3, expectedKeys: []int{1, 3, 2, 4, 5}},
+		{name: "adjacent, reversed", key1: 3, key2: 2, expectedKeys: []int{1, 3, 2, 4, 5}},
+		{name: "both ends", key1: 1, key2: 5, expectedKeys: []int{5, 2, 3, 4, 1}},
+		{name: "both ends, reversed", key1: 5, key2: 1, expectedKeys: []int{5, 2, 3, 4, 1}},
+		{name: "with the newest", key1: 5, key2: 2, expectedKeys: []int{1, 5, 3, 4, 2}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, options := range [][]any{nil, {WithIndexing[int, int]()}, {WithCompactStorage[int, int](0)}} {
+				om := New[int, int](options...)
+				for i := 1; i <= 5; i++ {
+					om.Set(i, 10*i)
+				}
+
+				require.NoError(t, om.Swap(testCase.key1, testCase.key2))
+
+				expectedValues := make([]int, len(testCase.expectedKeys))
+				for i, key := range testCase.expectedKeys {
+					expectedValues[i] = 10 * key
+				}
+				assertOrderedPairsEqual(t, om, testCase.expectedKeys, expectedValues)
+				if om.index != nil {
+					assertIndexConsistent(t, om)
+				}
+			}
+		})
+	}
+
+	om := New[int, int]()
+	om.Set(1, 1)
+	assert.Equal(t, &KeyNotFoundError[int]{2}, om.Swap(1, 2))
+	assert.Equal(t, &KeyNotFoundError[int]{2}, om.Swap(2, 1))
+}
+
+func TestRotate(t *testing.T) {
+	newMap := func() *OrderedMap[int, int] {
+		om := New[int, int]()
+		for i := 0; i < 5; i++ {
+			om.Set(i, i)
+		}
+		return om
+	}
+
+	for _, testCase := range []struct {
+		n                 int
+		expectedFromLeft  []int
+		expectedFromRight []int
+	}{
+		{n: 0, expectedFromLeft: []int{0, 1, 2, 3, 4}, expectedFromRight: []int{0, 1, 2, 3, 4}},
+		{n: 2, expectedFromLeft: []int{2, 3, 4, 0, 1}, expectedFromRight: []int{3, 4, 0, 1, 2}},
+		{n: 5, expectedFromLeft: []int{0, 1, 2, 3, 4}, expectedFromRight: []int{0, 1, 2, 3, 4}},
+		{n: 7, expectedFromLeft: []int{2, 3, 4, 0, 1}, expectedFromRight: []int{3, 4, 0, 1, 2}},
+		{n: -1, expectedFromLeft: []int{4, 0, 1, 2, 3}, expectedFromRight: []int{1, 2, 3, 4, 0}},
+		{n: -13, expectedFromLeft: []int{2, 3, 4, 0, 1}, expectedFromRight: []int{3, 4, 0, 1, 2}},
+	} {
+		om := newMap()
+		om.RotateLeft(testCase.n)
+		assertOrderedPairsEqual(t, om, testCase.expectedFromLeft, testCase.expectedFromLeft)
+
+		om = newMap()
+		om.RotateRight(testCase.n)
+		assertOrderedPairsEqual(t, om, testCase.expectedFromRight, testCase.expectedFromRight)
+	}
+
+	empty := New[int, int]()
+	empty.RotateLeft(3)
+	empty.RotateRight(3)
+	assertLenEqual(t, empty, 0)
+}
+
+func TestShuffleOrder(t *testing.T) {
+	n := 100
+	newMap := func() *OrderedMap[int, int] {
+		om := New[int, int]()
+		for i := 0; i < n; i++ {
+			om.Set(i, i)
+		}
+		return om
+	}
+
+	om1, om2 := newMap(), newMap()
+	om1.Shuffle(rand.NewPCG(28, 12))
+	om2.Shuffle(rand.NewPCG(28, 12))
+
+	// same source, same order
+	keys := slices.Collect(om1.KeysFromOldest())
+	assert.Equal(t, keys, slices.Collect(om2.KeysFromOldest()))
+	assertOrderedPairsEqual(t, om1, keys, keys)
+
+	// the pairs have actually been shuffled
+	assert.False(t, slices.IsSorted(keys))
+	slices.Sort(keys)
+	assert.Equal(t, slices.Collect(newMap().KeysFromOldest()), keys)
+
+	om3 := newMap()
+	om3.Shuffle(nil)
+	assert.Equal(t, n, om3.Len())
+}
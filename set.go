@@ -0,0 +1,225 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OrderedSet is a set that keeps track of the order in which elements were added.
+// It's backed by an OrderedMap, and so has the same performance characteristics.
+// It's marshalled to and unmarshalled from JSON and YAML as an array.
+//
+// Its zero value is only usable to unmarshal into; instances must otherwise be created with
+// NewSet or SetFrom.
+type OrderedSet[K comparable] struct {
+	om *OrderedMap[K, struct{}]
+}
+
+var (
+	_ json.Marshaler   = &OrderedSet[int]{}
+	_ json.Unmarshaler = &OrderedSet[int]{}
+	_ yaml.Marshaler   = &OrderedSet[int]{}
+	_ yaml.Unmarshaler = &OrderedSet[int]{}
+)
+
+// NewSet creates a new OrderedSet containing the given elements, in that order.
+func NewSet[K comparable](elements ...K) *OrderedSet[K] {
+	s := &OrderedSet[K]{om: New[K, struct{}](len(elements))}
+	for _, element := range elements {
+		s.Add(element)
+	}
+	return s
+}
+
+// SetFrom creates a new OrderedSet from an iterator over elements.
+func SetFrom[K comparable](i iter.Seq[K]) *OrderedSet[K] {
+	s := NewSet[K]()
+	for element := range i {
+		s.Add(element)
+	}
+	return s
+}
+
+// Add adds the element at the back of the set, and returns true; or returns false if the element
+// was already present, in which case its position doesn't change.
+func (s *OrderedSet[K]) Add(element K) bool {
+	_, present := s.om.GetOrSet(element, struct{}{})
+	return !present
+}
+
+// Remove removes the element, and returns whether it was present.
+func (s *OrderedSet[K]) Remove(element K) bool {
+	_, present := s.om.Delete(element)
+	return present
+}
+
+// Contains returns whether the element is present in the set.
+func (s *OrderedSet[K]) Contains(element K) bool {
+	if s.Len() == 0 {
+		return false
+	}
+	_, present := s.om.pairs[element]
+	return present
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[K]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.om.Len()
+}
+
+// Oldest returns the oldest element in the set; the boolean it returns is false iff the set is empty.
+func (s *OrderedSet[K]) Oldest() (element K, ok bool) {
+	if pair := s.om.Oldest(); pair != nil {
+		return pair.Key, true
+	}
+	return
+}
+
+// Newest returns the newest element in the set; the boolean it returns is false iff the set is empty.
+func (s *OrderedSet[K]) Newest() (element K, ok bool) {
+	if pair := s.om.Newest(); pair != nil {
+		return pair.Key, true
+	}
+	return
+}
+
+// MoveAfter moves element to its new position after mark.
+// Returns an error iff element or mark are not present in the set. If an error is returned,
+// it will be a KeyNotFoundError.
+func (s *OrderedSet[K]) MoveAfter(element, mark K) error {
+	return s.om.MoveAfter(element, mark)
+}
+
+// MoveBefore moves element to its new position before mark.
+// Returns an error iff element or mark are not present in the set. If an error is returned,
+// it will be a KeyNotFoundError.
+func (s *OrderedSet[K]) MoveBefore(element, mark K) error {
+	return s.om.MoveBefore(element, mark)
+}
+
+// MoveToBack moves element to the back of the set, i.e. makes it the newest element.
+// Returns an error iff element is not present in the set. If an error is returned,
+// it will be a KeyNotFoundError.
+func (s *OrderedSet[K]) MoveToBack(element K) error {
+	return s.om.MoveToBack(element)
+}
+
+// MoveToFront moves element to the front of the set, i.e. makes it the oldest element.
+// Returns an error iff element is not present in the set. If an error is returned,
+// it will be a KeyNotFoundError.
+func (s *OrderedSet[K]) MoveToFront(element K) error {
+	return s.om.MoveToFront(element)
+}
+
+// FromOldest returns an iterator over all the elements in the set, starting from the oldest one.
+func (s *OrderedSet[K]) FromOldest() iter.Seq[K] {
+	return s.om.KeysFromOldest()
+}
+
+// FromNewest returns an iterator over all the elements in the set, starting from the newest one.
+func (s *OrderedSet[K]) FromNewest() iter.Seq[K] {
+	return s.om.KeysFromNewest()
+}
+
+// Clone returns a copy of the set.
+func (s *OrderedSet[K]) Clone() *OrderedSet[K] {
+	if s == nil {
+		return nil
+	}
+	return &OrderedSet[K]{om: s.om.Clone()}
+}
+
+// Union returns a new set containing the elements of s, followed by those of other
+// that aren't in s, both in their original order.
+func (s *OrderedSet[K]) Union(other *OrderedSet[K]) *OrderedSet[K] {
+	union := s.Clone()
+	for element := range other.FromOldest() {
+		union.Add(element)
+	}
+	return union
+}
+
+// Intersection returns a new set containing the elements of s that are also in other,
+// in the same order as in s.
+func (s *OrderedSet[K]) Intersection(other *OrderedSet[K]) *OrderedSet[K] {
+	return s.filter(func(element K) bool { return other.Contains(element) })
+}
+
+// Difference returns a new set containing the elements of s that are not in other,
+// in the same order as in s.
+func (s *OrderedSet[K]) Difference(other *OrderedSet[K]) *OrderedSet[K] {
+	return s.filter(func(element K) bool { return !other.Contains(element) })
+}
+
+func (s *OrderedSet[K]) filter(predicate func(K) bool) *OrderedSet[K] {
+	result := &OrderedSet[K]{om: s.om.emptyClone(0)}
+	for element := range s.FromOldest() {
+		if predicate(element) {
+			result.om.insert(element, struct{}{})
+		}
+	}
+	return result
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *OrderedSet[K]) MarshalJSON() ([]byte, error) {
+	if s == nil || s.om == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.elements())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *OrderedSet[K]) UnmarshalJSON(data []byte) error {
+	var elements []K
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	s.addAll(elements)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (s *OrderedSet[K]) MarshalYAML() (interface{}, error) {
+	if s == nil || s.om == nil {
+		return nil, nil
+	}
+	return s.elements(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (s *OrderedSet[K]) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.SequenceNode {
+		return fmt.Errorf("set must be a YAML sequence, has %v", value.Kind)
+	}
+
+	var elements []K
+	if err := value.Decode(&elements); err != nil {
+		return err
+	}
+	s.addAll(elements)
+	return nil
+}
+
+func (s *OrderedSet[K]) elements() []K {
+	elements := make([]K, 0, s.Len())
+	for element := range s.FromOldest() {
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+func (s *OrderedSet[K]) addAll(elements []K) {
+	if s.om == nil {
+		s.om = New[K, struct{}](len(elements))
+	}
+	for _, element := range elements {
+		s.Add(element)
+	}
+}
@@ -0,0 +1,143 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOrderedSet(t *testing.T) {
+	s := NewSet("a", "b", "c", "a")
+	assertSetEqual(t, s, "a", "b", "c")
+
+	assert.True(t, s.Add("d"))
+	assert.False(t, s.Add("b"))
+	assertSetEqual(t, s, "a", "b", "c", "d")
+
+	assert.True(t, s.Contains("c"))
+	assert.False(t, s.Contains("i dont exist"))
+
+	assert.True(t, s.Remove("c"))
+	assert.False(t, s.Remove("c"))
+	assert.False(t, s.Contains("c"))
+	assertSetEqual(t, s, "a", "b", "d")
+
+	require.NoError(t, s.MoveToFront("d"))
+	require.NoError(t, s.MoveAfter("a", "b"))
+	assertSetEqual(t, s, "d", "b", "a")
+	require.NoError(t, s.MoveToBack("d"))
+	require.NoError(t, s.MoveBefore("a", "b"))
+	assertSetEqual(t, s, "a", "b", "d")
+	assert.Equal(t, &KeyNotFoundError[string]{"c"}, s.MoveToBack("c"))
+
+	oldest, ok := s.Oldest()
+	assert.True(t, ok)
+	assert.Equal(t, "a", oldest)
+	newest, ok := s.Newest()
+	assert.True(t, ok)
+	assert.Equal(t, "d", newest)
+
+	assert.Equal(t, []string{"d", "b", "a"}, slices.Collect(s.FromNewest()))
+
+	_, ok = NewSet[int]().Oldest()
+	assert.False(t, ok)
+	_, ok = NewSet[int]().Newest()
+	assert.False(t, ok)
+
+	assertSetEqual(t, SetFrom(slices.Values([]int{3, 1, 3, 2})), 3, 1, 2)
+
+	clone := s.Clone()
+	clone.Add("e")
+	assertSetEqual(t, s, "a", "b", "d")
+	assertSetEqual(t, clone, "a", "b", "d", "e")
+}
+
+func TestOrderedSetOperations(t *testing.T) {
+	s1 := NewSet(5, 1, 4, 2)
+	s2 := NewSet(3, 2, 6, 5)
+
+	assertSetEqual(t, s1.Union(s2), 5, 1, 4, 2, 3, 6)
+	assertSetEqual(t, s2.Union(s1), 3, 2, 6, 5, 1, 4)
+	assertSetEqual(t, s1.Intersection(s2), 5, 2)
+	assertSetEqual(t, s2.Intersection(s1), 2, 5)
+	assertSetEqual(t, s1.Difference(s2), 1, 4)
+	assertSetEqual(t, s2.Difference(s1), 3, 6)
+
+	// the operands are left untouched
+	assertSetEqual(t, s1, 5, 1, 4, 2)
+	assertSetEqual(t, s2, 3, 2, 6, 5)
+
+	empty := NewSet[int]()
+	assertSetEqual(t, s1.Union(empty), 5, 1, 4, 2)
+	assertSetEqual(t, s1.Intersection(empty))
+	assertSetEqual(t, s1.Difference(empty), 5, 1, 4, 2)
+	assertSetEqual(t, empty.Union(s1), 5, 1, 4, 2)
+}
+
+func TestOrderedSetMarshalling(t *testing.T) {
+	s := NewSet("b", "a", "<c>")
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(s)
+		require.NoError(t, err)
+		assert.Equal(t, `["b","a","\u003cc\u003e"]`, string(data))
+
+		var decoded OrderedSet[string]
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assertSetEqual(t, &decoded, "b", "a", "<c>")
+
+		// unmarshalling into an existing set adds to it
+		existing := NewSet("z")
+		require.NoError(t, json.Unmarshal([]byte(`["y","z","x","y"]`), existing))
+		assertSetEqual(t, existing, "z", "y", "x")
+
+		var nilSet *OrderedSet[string]
+		data, err = json.Marshal(nilSet)
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(data))
+
+		assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &decoded))
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		data, err := yaml.Marshal(s)
+		require.NoError(t, err)
+		assert.Equal(t, "- b\n- a\n- <c>\n", string(data))
+
+		var decoded OrderedSet[string]
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		assertSetEqual(t, &decoded, "b", "a", "<c>")
+
+		assert.Error(t, yaml.Unmarshal([]byte("a: 1\n"), &decoded))
+	})
+
+	t.Run("in a struct", func(t *testing.T) {
+		type withSet struct {
+			Tags *OrderedSet[int] `json:"tags" yaml:"tags"`
+		}
+
+		var decoded withSet
+		require.NoError(t, json.Unmarshal([]byte(`{"tags":[3,1,2]}`), &decoded))
+		assertSetEqual(t, decoded.Tags, 3, 1, 2)
+
+		data, err := yaml.Marshal(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, "tags:\n    - 3\n    - 1\n    - 2\n", string(data))
+	})
+}
+
+func assertSetEqual[K comparable](t *testing.T, s *OrderedSet[K], expected ...K) {
+	t.Helper()
+
+	assert.Equal(t, len(expected), s.Len())
+	if len(expected) == 0 {
+		assert.Empty(t, s.elements())
+	} else {
+		assert.Equal(t, expected, s.elements())
+	}
+	assertLenEqual(t, s.om, len(expected))
+}
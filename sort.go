@@ -0,0 +1,56 @@
+package orderedmap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortFunc sorts the map's pairs in place, in ascending order as determined by cmp,
+// which should return a negative number when a < b, a positive number when a > b and zero when a == b.
+// The sort is not guaranteed to be stable.
+// Pairs are re-linked rather than re-allocated, so that pointers obtained e.g. from
+// GetPair remain valid.
+func (om *OrderedMap[K, V]) SortFunc(cmp func(a, b *Pair[K, V]) int) {
+	pairs := om.pairSlice()
+	slices.SortFunc(pairs, cmp)
+	om.reorder(pairs)
+}
+
+// SortStableFunc is the same as SortFunc, but keeps the original order of equal pairs.
+func (om *OrderedMap[K, V]) SortStableFunc(cmp func(a, b *Pair[K, V]) int) {
+	pairs := om.pairSlice()
+	slices.SortStableFunc(pairs, cmp)
+	om.reorder(pairs)
+}
+
+// SortByKey sorts the map's pairs in place, in ascending order of their keys.
+func SortByKey[K cmp.Ordered, V any](om *OrderedMap[K, V]) {
+	om.SortFunc(func(a, b *Pair[K, V]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+}
+
+// SortByValue sorts the map's pairs in place, in ascending order of their values.
+// Pairs with equal values keep their original order.
+func SortByValue[K comparable, V cmp.Ordered](om *OrderedMap[K, V]) {
+	om.SortStableFunc(func(a, b *Pair[K, V]) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+}
+
+// pairSlice returns all the map's pairs, from oldest to newest.
+func (om *OrderedMap[K, V]) pairSlice() []*Pair[K, V] {
+	pairs := make([]*Pair[K, V], 0, om.Len())
+	for pair := range om.pairsFromOldest() {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// reorder re-links all the map's pairs so that they end up in the given order.
+// pairs must contain all of the map's pairs, and only them.
+func (om *OrderedMap[K, V]) reorder(pairs []*Pair[K, V]) {
+	for _, pair := range pairs {
+		om.moveToBack(pair)
+	}
+}
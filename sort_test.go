@@ -0,0 +1,106 @@
+package orderedmap
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSort(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		options []any
+	}{
+		{name: "default"},
+		{name: "indexed", options: []any{WithIndexing[string, int]()}},
+		{name: "compact storage", options: []any{WithCompactStorage[string, int](0)}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			newMap := func() *OrderedMap[string, int] {
+				om := New[string, int](testCase.options...)
+				om.Set("d", 2)
+				om.Set("b", 3)
+				om.Set("a", 1)
+				om.Set("e", 2)
+				om.Set("c", 5)
+				return om
+			}
+
+			t.Run("by key", func(t *testing.T) {
+				om := newMap()
+				pair := om.GetPair("e")
+
+				SortByKey(om)
+				assertOrderedPairsEqual(t, om,
+					[]string{"a", "b", "c", "d", "e"},
+					[]int{1, 3, 5, 2, 2})
+
+				// outstanding pairs remain valid
+				assert.Same(t, pair, om.GetPair("e"))
+				assert.Equal(t, "d", pair.Prev().Key)
+				if om.index != nil {
+					assertIndexConsistent(t, om)
+				}
+			})
+
+			t.Run("by value", func(t *testing.T) {
+				om := newMap()
+
+				SortByValue(om)
+				assertOrderedPairsEqual(t, om,
+					[]string{"a", "d", "e", "b", "c"},
+					[]int{1, 2, 2, 3, 5})
+			})
+
+			t.Run("with a custom comparator", func(t *testing.T) {
+				om := newMap()
+
+				om.SortFunc(func(a, b *Pair[string, int]) int {
+					return strings.Compare(b.Key, a.Key)
+				})
+				assertOrderedPairsEqual(t, om,
+					[]string{"e", "d", "c", "b", "a"},
+					[]int{2, 2, 5, 3, 1})
+			})
+
+			t.Run("stable, with a custom comparator", func(t *testing.T) {
+				om := newMap()
+
+				om.SortStableFunc(func(a, b *Pair[string, int]) int {
+					return cmp.Compare(b.Value, a.Value)
+				})
+				assertOrderedPairsEqual(t, om,
+					[]string{"c", "b", "d", "e", "a"},
+					[]int{5, 3, 2, 2, 1})
+			})
+		})
+	}
+}
+
+func TestSortLargeMap(t *testing.T) {
+	n := 1000
+	om := New[string, int]()
+	for i := n - 1; i >= 0; i-- {
+		om.Set(fmt.Sprintf("%04d", i), i)
+	}
+
+	SortByKey(om)
+
+	i := 0
+	for k, v := range om.FromOldest() {
+		assert.Equal(t, fmt.Sprintf("%04d", i), k)
+		assert.Equal(t, i, v)
+		i++
+	}
+	assertLenEqual(t, om, n)
+}
+
+func TestSortEmptyMap(t *testing.T) {
+	om := New[int, int]()
+	SortByKey(om)
+	SortByValue(om)
+	assertLenEqual(t, om, 0)
+}
@@ -0,0 +1,267 @@
+package orderedmap
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// Clock gives the current time to TTLMaps; it allows tests to control time.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// TTLMap is an ordered map whose entries expire after a time-to-live.
+//
+// Pairs are kept in expiry order: the oldest pair is the one expiring the soonest.
+// When all entries share the same TTL, that's the same as insertion order, and all operations
+// are constant-time; setting a pair with a shorter TTL than those of the newest pairs takes
+// time linear to the number of pairs that it needs to be inserted before.
+//
+// Expired entries are invisible to all read operations, and get removed either lazily,
+// when accessed, or when calling Sweep, which a background janitor can do periodically,
+// see StartJanitor.
+//
+// Unlike OrderedMap, TTLMap is safe for concurrent use. Its iterators operate on a snapshot
+// of the map taken when the iteration starts.
+type TTLMap[K comparable, V any] struct {
+	mutex      sync.Mutex
+	om         *OrderedMap[K, ttlEntry[V]]
+	defaultTTL time.Duration
+	clock      Clock
+
+	stopJanitor chan struct{}
+	janitorDone chan struct{}
+}
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+const (
+	invalidTTLMessage             = `TTLs must be strictly positive`
+	invalidJanitorIntervalMessage = `janitor intervals must be strictly positive`
+)
+
+// NewTTL creates a new TTLMap, where entries expire after defaultTTL unless
+// specified otherwise with SetWithTTL.
+// clock can be nil, in which case the system clock is used.
+// Panics if defaultTTL is not strictly positive.
+func NewTTL[K comparable, V any](defaultTTL time.Duration, clock Clock) *TTLMap[K, V] {
+	if defaultTTL <= 0 {
+		panic(invalidTTLMessage)
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &TTLMap[K, V]{
+		om:         New[K, ttlEntry[V]](),
+		defaultTTL: defaultTTL,
+		clock:      clock,
+	}
+}
+
+// Get looks for the given key, and returns the value associated with it,
+// or V's nil value if not found or expired. The boolean it returns says whether
+// the key is present in the map and not expired.
+func (tm *TTLMap[K, V]) Get(key K) (val V, present bool) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return tm.get(key, tm.clock.Now())
+}
+
+func (tm *TTLMap[K, V]) get(key K, now time.Time) (val V, present bool) {
+	pair := tm.om.GetPair(key)
+	if pair == nil {
+		return
+	}
+	if !now.Before(pair.Value.expiresAt) {
+		tm.om.Delete(key)
+		return
+	}
+	return pair.Value.value, true
+}
+
+// Set sets the key-value pair with the map's default TTL, and returns what `Get`
+// would have returned on that key prior to the call to `Set`.
+// The pair's expiry is reset even if the key was already present.
+func (tm *TTLMap[K, V]) Set(key K, value V) (V, bool) {
+	return tm.SetWithTTL(key, value, tm.defaultTTL)
+}
+
+// SetWithTTL is the same as Set, except that the pair expires after the given TTL instead
+// of the map's default one.
+// Panics if ttl is not strictly positive.
+func (tm *TTLMap[K, V]) SetWithTTL(key K, value V, ttl time.Duration) (val V, present bool) {
+	if ttl <= 0 {
+		panic(invalidTTLMessage)
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	now := tm.clock.Now()
+	val, present = tm.get(key, now)
+
+	entry := ttlEntry[V]{
+		value:     value,
+		expiresAt: now.Add(ttl),
+	}
+	if present {
+		tm.om.Delete(key)
+	}
+
+	// find the newest pair that expires no later than the new one
+	mark := tm.om.Newest()
+	for mark != nil && mark.Value.expiresAt.After(entry.expiresAt) {
+		mark = mark.Prev()
+	}
+
+	tm.om.Set(key, entry)
+	switch {
+	case mark == nil:
+		_ = tm.om.MoveToFront(key)
+	case mark.Next() != tm.om.GetPair(key):
+		_ = tm.om.MoveAfter(key, mark.Key)
+	}
+
+	return
+}
+
+// Delete removes the key-value pair, and returns what `Get` would have returned
+// on that key prior to the call to `Delete`.
+func (tm *TTLMap[K, V]) Delete(key K) (val V, present bool) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if val, present = tm.get(key, tm.clock.Now()); present {
+		tm.om.Delete(key)
+	}
+	return
+}
+
+// Len returns the number of entries in the map that haven't expired yet.
+// It sweeps expired entries first.
+func (tm *TTLMap[K, V]) Len() int {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.sweep(tm.clock.Now())
+	return tm.om.Len()
+}
+
+// Sweep removes all the expired entries from the map, and returns how many were removed.
+// It runs in time linear to the number of expired entries.
+func (tm *TTLMap[K, V]) Sweep() int {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return tm.sweep(tm.clock.Now())
+}
+
+func (tm *TTLMap[K, V]) sweep(now time.Time) (removed int) {
+	for pair := tm.om.Oldest(); pair != nil && !now.Before(pair.Value.expiresAt); pair = tm.om.Oldest() {
+		tm.om.Delete(pair.Key)
+		removed++
+	}
+	return
+}
+
+// StartJanitor starts a background goroutine that calls Sweep every interval,
+// until StopJanitor is called. Calling StartJanitor when a janitor is already
+// running replaces it.
+// Panics if interval is not strictly positive.
+func (tm *TTLMap[K, V]) StartJanitor(interval time.Duration) {
+	if interval <= 0 {
+		panic(invalidJanitorIntervalMessage)
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				tm.Sweep()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	tm.replaceJanitor(stop, done)
+}
+
+// StopJanitor stops the background janitor started by StartJanitor, if any, and waits
+// for it to exit.
+func (tm *TTLMap[K, V]) StopJanitor() {
+	tm.replaceJanitor(nil, nil)
+}
+
+// replaceJanitor registers the given janitor, then stops the previous one, if any.
+// Both happen under a single lock acquisition, so that concurrent calls can't leak a
+// janitor; waiting for the previous one to exit must happen after releasing the lock,
+// since it might be in the middle of a sweep.
+func (tm *TTLMap[K, V]) replaceJanitor(stop, done chan struct{}) {
+	tm.mutex.Lock()
+	previousStop, previousDone := tm.stopJanitor, tm.janitorDone
+	tm.stopJanitor, tm.janitorDone = stop, done
+	tm.mutex.Unlock()
+
+	if previousStop != nil {
+		close(previousStop)
+		<-previousDone
+	}
+}
+
+// FromOldest returns an iterator over all the non-expired key-value pairs in the map,
+// starting from the one expiring the soonest. It iterates over a snapshot taken when
+// the iteration starts.
+func (tm *TTLMap[K, V]) FromOldest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		snapshot := tm.snapshot()
+		for i := 0; i < len(snapshot); i++ {
+			if !yield(snapshot[i].Key, snapshot[i].Value) {
+				return
+			}
+		}
+	}
+}
+
+// FromNewest returns an iterator over all the non-expired key-value pairs in the map,
+// starting from the one expiring the latest. It iterates over a snapshot taken when
+// the iteration starts.
+func (tm *TTLMap[K, V]) FromNewest() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		snapshot := tm.snapshot()
+		for i := len(snapshot) - 1; i >= 0; i-- {
+			if !yield(snapshot[i].Key, snapshot[i].Value) {
+				return
+			}
+		}
+	}
+}
+
+func (tm *TTLMap[K, V]) snapshot() []Pair[K, V] {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	tm.sweep(tm.clock.Now())
+
+	snapshot := make([]Pair[K, V], 0, tm.om.Len())
+	for pair := tm.om.Oldest(); pair != nil; pair = pair.Next() {
+		snapshot = append(snapshot, Pair[K, V]{Key: pair.Key, Value: pair.Value.value})
+	}
+	return snapshot
+}
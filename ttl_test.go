@@ -0,0 +1,200 @@
+package orderedmap
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2023, time.June, 27, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTTLMapExpiry(t *testing.T) {
+	clock := newFakeClock()
+	tm := NewTTL[string, int](10*time.Second, clock)
+
+	tm.Set("a", 1)
+	clock.Advance(time.Second)
+	tm.Set("b", 2)
+	clock.Advance(time.Second)
+	tm.Set("c", 3)
+	assertTTLMapEqual(t, tm, []string{"a", "b", "c"}, []int{1, 2, 3})
+
+	clock.Advance(8 * time.Second)
+	// a is now expired
+	value, present := tm.Get("a")
+	assert.False(t, present)
+	assert.Equal(t, 0, value)
+	assertTTLMapEqual(t, tm, []string{"b", "c"}, []int{2, 3})
+
+	// setting an existing key resets its expiry
+	oldValue, present := tm.Set("b", 20)
+	assert.True(t, present)
+	assert.Equal(t, 2, oldValue)
+	assertTTLMapEqual(t, tm, []string{"c", "b"}, []int{3, 20})
+
+	clock.Advance(5 * time.Second)
+	assertTTLMapEqual(t, tm, []string{"b"}, []int{20})
+
+	// re-setting an expired key is the same as setting a new one
+	oldValue, present = tm.Set("c", 30)
+	assert.False(t, present)
+	assert.Equal(t, 0, oldValue)
+	assertTTLMapEqual(t, tm, []string{"b", "c"}, []int{20, 30})
+
+	value, present = tm.Delete("b")
+	assert.True(t, present)
+	assert.Equal(t, 20, value)
+	_, present = tm.Delete("b")
+	assert.False(t, present)
+	assertTTLMapEqual(t, tm, []string{"c"}, []int{30})
+}
+
+func TestTTLMapPerEntryTTL(t *testing.T) {
+	clock := newFakeClock()
+	tm := NewTTL[string, int](10*time.Second, clock)
+
+	tm.Set("a", 1)
+	tm.Set("b", 2)
+	tm.SetWithTTL("c", 3, time.Second)
+	tm.SetWithTTL("d", 4, 20*time.Second)
+	tm.SetWithTTL("e", 5, 5*time.Second)
+	tm.Set("f", 6)
+
+	// pairs are in expiry order
+	assertTTLMapEqual(t, tm,
+		[]string{"c", "e", "a", "b", "f", "d"},
+		[]int{3, 5, 1, 2, 6, 4})
+
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, 2, tm.Sweep())
+	assert.Equal(t, 0, tm.Sweep())
+	assert.Equal(t, 4, tm.om.Len())
+
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, 3, tm.Sweep())
+	assertTTLMapEqual(t, tm, []string{"d"}, []int{4})
+
+	assert.PanicsWithValue(t, invalidTTLMessage, func() {
+		tm.SetWithTTL("g", 7, 0)
+	})
+	assert.PanicsWithValue(t, invalidTTLMessage, func() {
+		_ = NewTTL[string, int](-time.Second, nil)
+	})
+}
+
+func TestTTLMapLenSweeps(t *testing.T) {
+	clock := newFakeClock()
+	tm := NewTTL[int, int](time.Minute, clock)
+
+	for i := 0; i < 10; i++ {
+		tm.Set(i, i)
+		clock.Advance(time.Second)
+	}
+	assert.Equal(t, 10, tm.Len())
+
+	clock.Advance(54 * time.Second)
+	assert.Equal(t, 5, tm.Len())
+	assert.Equal(t, 5, tm.om.Len())
+}
+
+func TestTTLMapJanitor(t *testing.T) {
+	clock := newFakeClock()
+	tm := NewTTL[int, int](time.Minute, clock)
+
+	for i := 0; i < 10; i++ {
+		tm.Set(i, i)
+	}
+	clock.Advance(time.Hour)
+
+	tm.StartJanitor(time.Millisecond)
+	// restarting replaces the current janitor
+	tm.StartJanitor(time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		tm.mutex.Lock()
+		defer tm.mutex.Unlock()
+		return tm.om.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	tm.StopJanitor()
+	// stopping again is a no-op
+	tm.StopJanitor()
+
+	assert.PanicsWithValue(t, invalidJanitorIntervalMessage, func() {
+		tm.StartJanitor(0)
+	})
+}
+
+func TestTTLMapConcurrentJanitorStarts(t *testing.T) {
+	tm := NewTTL[int, int](time.Minute, nil)
+	goroutines := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tm.StartJanitor(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+
+	tm.StopJanitor()
+
+	// none of the janitors leaked
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+}
+
+func assertTTLMapEqual[K comparable, V any](t *testing.T, tm *TTLMap[K, V], expectedKeys []K, expectedValues []V) {
+	t.Helper()
+
+	assert.Equal(t, len(expectedKeys), tm.Len())
+
+	var keys []K
+	var values []V
+	for k, v := range tm.FromOldest() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	assert.Equal(t, expectedKeys, keys)
+	assert.Equal(t, expectedValues, values)
+
+	i := len(expectedKeys) - 1
+	for k, v := range tm.FromNewest() {
+		assert.Equal(t, expectedKeys[i], k)
+		assert.Equal(t, expectedValues[i], v)
+		i--
+	}
+
+	for i, k := range expectedKeys {
+		value, present := tm.Get(k)
+		assert.True(t, present)
+		assert.Equal(t, expectedValues[i], value)
+	}
+}
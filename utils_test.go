@@ -57,6 +57,9 @@ func assertLenEqual[K comparable, V any](t *testing.T, orderedMap *OrderedMap[K,
 
 	// also check the list length, for good measure
 	assert.Equal(t, expectedLen, orderedMap.list.Len())
+	if orderedMap.index != nil {
+		assert.Equal(t, expectedLen, nodeSize(orderedMap.index.root))
+	}
 }
 
 func randomHexString(t *testing.T, length int) string {
@@ -0,0 +1,68 @@
+package orderedmap
+
+// GetPtr looks for the given key, and returns a pointer to the value associated with it,
+// or nil if not found. It allows updating values in place, e.g. a single field of a struct value.
+// The pointer remains valid until the key is deleted from the map.
+// With the AccessOrder policy, the pair is moved to the back of the map.
+func (om *OrderedMap[K, V]) GetPtr(key K) *V {
+	pair, present := om.pairs[key]
+	if !present {
+		return nil
+	}
+	om.read(pair)
+	return &pair.Value
+}
+
+// PairView is a read-only view of a pair: unlike with a *Pair, its key can't be changed, which
+// would corrupt the map. Its value can still be updated through ValuePtr.
+// The zero PairView doesn't point to any pair, and must not be used.
+type PairView[K comparable, V any] struct {
+	pair *Pair[K, V]
+}
+
+// GetView looks for the given key, and returns a view of the pair associated with it.
+// The boolean it returns says whether the key is present in the map.
+func (om *OrderedMap[K, V]) GetView(key K) (PairView[K, V], bool) {
+	return viewOf(om.pairs[key])
+}
+
+// OldestView returns a view of the oldest pair. The boolean it returns is false iff the map is empty.
+// It's meant to be used to iterate on the ordered map's pairs from the oldest to the newest, e.g.:
+// for view, ok := orderedMap.OldestView(); ok; view, ok = view.Next() { fmt.Println(view.Key()) }
+func (om *OrderedMap[K, V]) OldestView() (PairView[K, V], bool) {
+	return viewOf(om.Oldest())
+}
+
+// NewestView returns a view of the newest pair. The boolean it returns is false iff the map is empty.
+func (om *OrderedMap[K, V]) NewestView() (PairView[K, V], bool) {
+	return viewOf(om.Newest())
+}
+
+func viewOf[K comparable, V any](pair *Pair[K, V]) (PairView[K, V], bool) {
+	return PairView[K, V]{pair: pair}, pair != nil
+}
+
+// Key returns the pair's key.
+func (v PairView[K, V]) Key() K {
+	return v.pair.Key
+}
+
+// Value returns the pair's value.
+func (v PairView[K, V]) Value() V {
+	return v.pair.Value
+}
+
+// ValuePtr returns a pointer to the pair's value, allowing to update it in place.
+func (v PairView[K, V]) ValuePtr() *V {
+	return &v.pair.Value
+}
+
+// Next returns a view of the next pair. The boolean it returns is false iff this is the newest pair.
+func (v PairView[K, V]) Next() (PairView[K, V], bool) {
+	return viewOf(v.pair.Next())
+}
+
+// Prev returns a view of the previous pair. The boolean it returns is false iff this is the oldest pair.
+func (v PairView[K, V]) Prev() (PairView[K, V], bool) {
+	return viewOf(v.pair.Prev())
+}
@@ -0,0 +1,75 @@
+package orderedmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type viewTestStruct struct {
+	count int
+	name  string
+}
+
+func TestGetPtr(t *testing.T) {
+	om := New[string, viewTestStruct]()
+	om.Set("a", viewTestStruct{name: "a"})
+	om.Set("b", viewTestStruct{name: "b"})
+
+	ptr := om.GetPtr("a")
+	if assert.NotNil(t, ptr) {
+		ptr.count++
+		ptr.count++
+	}
+	assert.Nil(t, om.GetPtr("i dont exist"))
+
+	assertOrderedPairsEqual(t, om,
+		[]string{"a", "b"},
+		[]viewTestStruct{{count: 2, name: "a"}, {name: "b"}})
+}
+
+func TestGetPtrWithAccessOrder(t *testing.T) {
+	om := New[int, int](WithOrderingPolicy[int, int](AccessOrder))
+	om.Set(1, 1)
+	om.Set(2, 2)
+
+	*om.GetPtr(1) = 10
+	assertOrderedPairsEqual(t, om, []int{2, 1}, []int{2, 10})
+}
+
+func TestPairView(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	view, ok := om.GetView("b")
+	if assert.True(t, ok) {
+		assert.Equal(t, "b", view.Key())
+		assert.Equal(t, 2, view.Value())
+		*view.ValuePtr() = 20
+	}
+	_, ok = om.GetView("i dont exist")
+	assert.False(t, ok)
+
+	var keys []string
+	var values []int
+	for view, ok := om.OldestView(); ok; view, ok = view.Next() {
+		keys = append(keys, view.Key())
+		values = append(values, view.Value())
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Equal(t, []int{1, 20, 3}, values)
+
+	keys = nil
+	for view, ok := om.NewestView(); ok; view, ok = view.Prev() {
+		keys = append(keys, view.Key())
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, keys)
+
+	empty := New[string, int]()
+	_, ok = empty.OldestView()
+	assert.False(t, ok)
+	_, ok = empty.NewestView()
+	assert.False(t, ok)
+}
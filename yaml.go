@@ -53,6 +53,7 @@ func (om *OrderedMap[K, V]) UnmarshalYAML(value *yaml.Node) error {
 		om.initialize(0, om.disableHTMLEscape)
 	}
 
+	seen := om.seenKeys()
 	for index := 0; index < len(value.Content); index += 2 {
 		var key K
 		var val V
@@ -64,7 +65,10 @@ func (om *OrderedMap[K, V]) UnmarshalYAML(value *yaml.Node) error {
 			return err
 		}
 
-		om.Set(key, val)
+		if !om.setDecoded(seen, key, val) {
+			keyNode := value.Content[index]
+			return &DuplicateKeyError[K]{Key: key, Line: keyNode.Line, Column: keyNode.Column}
+		}
 	}
 
 	return nil